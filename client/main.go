@@ -5,13 +5,30 @@ import (
 	"github.com/alberttduong/card-game/tui"
 	"github.com/nsf/termbox-go"
 	_ "embed"
+	"flag"
+	"fmt"
+	"os"
 )
 
 //go:embed cards.json 
 var data []byte
 
 func main() {
-	err := termbox.Init()
+	var cardPaths []string
+	flag.Func("cards", "card file to load after the built in cards, can be repeated", 
+		func(path string) error {
+			cardPaths = append(cardPaths, path)
+			return nil
+		})
+	flag.Parse()
+
+	cards, err := game.ReadCardFiles(data, cardPaths...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	err = termbox.Init()
     if err != nil {
         panic(err)
     }
@@ -25,8 +42,6 @@ func main() {
 	}
 	*/
 
-	cards := game.GetCardData(data) 	
-
 	screen := tui.InitScreen(cards)
 	//screen.Game = tui.NewScreen(cards, g)
 
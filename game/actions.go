@@ -0,0 +1,306 @@
+package game
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Action is a single move that can be applied to a State with Apply.
+// Args returns the equivalent command for State.Execute.
+type Action interface {
+	Args() []string
+	apply(State) (State, error)
+}
+
+// Loc points at a wizard, permanent or dragon on the board
+type Loc struct {
+	Area cardType
+	PID  int
+	ID   int
+}
+
+func (l Loc) target() target {
+	return target{area: l.Area, pID: playerID(l.PID), id: l.ID}
+}
+
+func itoa(nums ...int) []string {
+	res := make([]string, len(nums))
+	for i, n := range nums {
+		res[i] = strconv.Itoa(n)
+	}
+	return res
+}
+
+func withName(name string, nums ...int) []string {
+	return append([]string{name}, itoa(nums...)...)
+}
+
+type PlayFromHand struct {
+	Index int
+}
+
+func (a PlayFromHand) Args() []string {
+	return withName("play", a.Index)
+}
+
+func (a PlayFromHand) apply(s State) (State, error) {
+	if err := s.canPlay(); err != nil {
+		return s, err
+	}
+	h := s.Players[s.CurrentPlayer].Hand
+	if a.Index < 0 || a.Index > len(h)-1 {
+		return s, InputErr{"Hand index out of bounds"}
+	}
+
+	card, err := s.cardData(h[a.Index])
+	if err != nil {
+		return s, err
+	}
+
+	game, err := s.play(s.CurrentPlayer, card)
+	if err != nil {
+		return s, err
+	}
+
+	game, err = game.removeFromHand(game.CurrentPlayer, a.Index)
+	if err != nil {
+		return s, err
+	}
+	if game.awaiting.spell {
+		game.awaiting.fromHand = true
+	}
+
+	return game, nil
+}
+
+// Named DeclareAttack since Attack is the card's attack data
+type DeclareAttack struct {
+	Attacker Loc
+	AtkNum   int
+	Defender Loc
+}
+
+func (a DeclareAttack) Args() []string {
+	return withName("atk",
+		int(a.Attacker.Area), a.Attacker.PID, a.Attacker.ID, a.AtkNum,
+		int(a.Defender.Area), a.Defender.PID, a.Defender.ID)
+}
+
+func (a DeclareAttack) apply(s State) (State, error) {
+	atkr := a.Attacker.target()
+	atkr.atkNum = a.AtkNum
+
+	newS, err := s.attack(atkr, a.Defender.target())
+	if err != nil {
+		return s, err
+	}
+	return newS, nil
+}
+
+type Target struct {
+	PID, ID int
+}
+
+func (a Target) Args() []string {
+	return withName("target", a.PID, a.ID)
+}
+
+func (a Target) apply(s State) (State, error) {
+	return s.target(target{pID: playerID(a.PID), id: a.ID})
+}
+
+type TargetPerm struct {
+	PID, ID int
+}
+
+func (a TargetPerm) Args() []string {
+	return withName("targetperm", a.PID, a.ID)
+}
+
+func (a TargetPerm) apply(s State) (State, error) {
+	return s.target(target{area: Permanent, pID: playerID(a.PID), id: a.ID})
+}
+
+// Picks a card out of the current player's deck, used by Extractio
+type TargetDeck struct {
+	Card CardName
+}
+
+func (a TargetDeck) Args() []string {
+	return []string{"targetdeck", a.Card.ID()}
+}
+
+func (a TargetDeck) apply(s State) (State, error) {
+	return s.target(target{area: Deck, id: int(a.Card)})
+}
+
+type Activate struct {
+	PID, ID int
+}
+
+func (a Activate) Args() []string {
+	return withName("activate", a.PID, a.ID)
+}
+
+func (a Activate) apply(s State) (State, error) {
+	return s.activatePerm(PermTarget{playerID(a.PID), a.ID})
+}
+
+type EndTurn struct{}
+
+func (a EndTurn) Args() []string {
+	return []string{"end"}
+}
+
+func (a EndTurn) apply(s State) (State, error) {
+	return s.endTurn()
+}
+
+// Discards a card when the hand is over the limit at the end of the turn
+type DiscardFromHand struct {
+	Index int
+}
+
+func (a DiscardFromHand) Args() []string {
+	return withName("discard", a.Index)
+}
+
+func (a DiscardFromHand) apply(s State) (State, error) {
+	return s.discardFromHand(a.Index)
+}
+
+// Puts cards from the opening hand back and redraws as many. No indices
+// means the whole hand.
+type Mulligan struct {
+	Indices []int
+}
+
+func (a Mulligan) Args() []string {
+	return withName("mulligan", a.Indices...)
+}
+
+func (a Mulligan) apply(s State) (State, error) {
+	return s.mulligan(a.Indices)
+}
+
+// Keeps the opening hand
+type Keep struct{}
+
+func (a Keep) Args() []string {
+	return []string{"keep"}
+}
+
+func (a Keep) apply(s State) (State, error) {
+	return s.keep()
+}
+
+type Draw struct{}
+
+func (a Draw) Args() []string {
+	return []string{"draw"}
+}
+
+func (a Draw) apply(s State) (State, error) {
+	return s.drawCard(s.CurrentPlayer)
+}
+
+type ShowDeck struct{}
+
+func (a ShowDeck) Args() []string {
+	return []string{"showdeck"}
+}
+
+func (a ShowDeck) apply(s State) (State, error) {
+	return s.showDeck(s.CurrentPlayer), nil
+}
+
+// Debug action, puts a card into play without it being in the hand
+type Create struct {
+	Card CardName
+}
+
+func (a Create) Args() []string {
+	return []string{"create", a.Card.ID()}
+}
+
+func (a Create) apply(s State) (State, error) {
+	card, err := s.cardData(a.Card)
+	if err != nil {
+		return s, err
+	}
+
+	game, err := s.play(s.CurrentPlayer, card)
+	if err != nil {
+		return s, err
+	}
+	return game, nil
+}
+
+// Backs out of the pending target, see State.cancel
+type Cancel struct{}
+
+func (a Cancel) Args() []string {
+	return []string{"cancel"}
+}
+
+func (a Cancel) apply(s State) (State, error) {
+	return s.cancel()
+}
+
+// Debug action
+type SetMana struct {
+	Mana int
+}
+
+func (a SetMana) Args() []string {
+	return withName("setmana", a.Mana)
+}
+
+func (a SetMana) apply(s State) (State, error) {
+	return s.setMana(a.Mana), nil
+}
+
+func (s State) cardData(n CardName) (Playable, error) {
+	if s.cards == nil {
+		return nil, errors.New("No card data loaded")
+	}
+	if _, ok := LookupCard(s.cards, n); !ok {
+		return nil, InvalidCardErr
+	}
+	return CardFromName(s.cards, n), nil
+}
+
+// Card data used when actions need to create cards
+func (s State) SetCardData(cards []Cdata) State {
+	s.cards = cards
+	return s
+}
+
+// Apply works on a clone and is all or nothing: s itself is never
+// modified and it is returned unchanged along with any error.
+func (s State) Apply(a Action) (State, error) {
+	if s.gameOver {
+		return s, GameOverErr
+	}
+	if _, ok := a.(DiscardFromHand); s.awaiting.discarding && !ok {
+		return s, DiscardFirstErr
+	}
+	switch a.(type) {
+	case Mulligan, Keep:
+	default:
+		if s.phase == MulliganPhase {
+			return s, MulliganFirstErr
+		}
+	}
+
+	next, err := a.apply(s.Clone())
+	if err != nil {
+		return s, err
+	}
+	s = next.checkGameOver()
+	if !s.gameOver && s.eliminated[s.CurrentPlayer] {
+		s = s.cancelAwait()
+		s, _ = s.endTurn()
+	}
+	return s, nil
+}
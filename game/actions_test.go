@@ -0,0 +1,219 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_ParseAction(t *testing.T) {
+	actions := []Action{
+		PlayFromHand{2},
+		DeclareAttack{Loc{Permanent, 1, 0}, 0, Loc{Wizard, 0, 2}},
+		Target{1, 2},
+		TargetPerm{0, 3},
+		TargetDeck{Librarian},
+		Activate{1, 1},
+		EndTurn{},
+		Draw{},
+		ShowDeck{},
+		Create{PyrusBalio},
+		SetMana{4},
+	}
+
+	for _, a := range actions {
+		parsed, err := ParseAction(a.Args()...)
+		if err != nil {
+			t.Errorf("%v: %v", a.Args(), err)
+			continue
+		}
+		if parsed != a {
+			t.Errorf("expected %#v got %#v", a, parsed)
+		}
+	}
+
+	if _, err := ParseAction(); err == nil {
+		t.Error("expected error for empty command")
+	}
+	if _, err := ParseAction("target", "1"); err == nil {
+		t.Error("expected error for missing args")
+	}
+
+	for _, args := range [][]string{{"create", "pyrus-balio"}, {"create", "11"}} {
+		if a, err := ParseAction(args...); err != nil || a != (Create{PyrusBalio}) {
+			t.Errorf("%v: expected Pyrus Balio got %v %v", args, a, err)
+		}
+	}
+	for _, args := range [][]string{{"targetdeck", "999"}, {"targetdeck", "no-card"}, {"create"}} {
+		if _, err := ParseAction(args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+
+	var c CardName
+	if err := json.Unmarshal([]byte("999"), &c); err == nil {
+		t.Error("expected unknown card numbers to fail to load")
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	g := testGame(nil, []CardName{Librarian})
+	g.Players[0].Hand = []CardName{Librarian, PyrusBalio}
+
+	g, err := g.Apply(PlayFromHand{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, _ = g.Apply(PlayFromHand{0})
+	g, err = g.Apply(Target{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.checkHpIs(t, 7)
+	g.checkHandSize(t, 0)
+
+	if _, err := g.Apply(PlayFromHand{0}); err == nil {
+		t.Error("expected out of bounds error")
+	}
+}
+
+func Test_ApplyLeavesOriginal(t *testing.T) {
+	g := testGame([]CardName{Librarian, Meteorus}, []CardName{Librarian})
+
+	next, err := g.Apply(Activate{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Permanents[PermTarget{0, 0}].Activated {
+		t.Error("Apply activated the perm in the original state")
+	}
+	if !next.Permanents[PermTarget{0, 0}].Activated {
+		t.Error("expected perm to be activated")
+	}
+	hp := g.CardHp(0, 0) + g.CardHp(1, 0)
+	if hp != 16 {
+		t.Errorf("Meteorus damaged the original state")
+	}
+}
+
+func Test_FailedActionsLeaveState(t *testing.T) {
+	cases := map[string]struct {
+		setup  func() State
+		action Action
+	}{
+		"perm on a full board": {func() State {
+			g, _ := newGame(2, DefaultRules())
+			for range MaxPermLen {
+				g, _, _ = g.addPerm(0, Perm{CName: Aquarius})
+			}
+			g.Players[0].Hand = []CardName{Librarius}
+			return g.setMana(5)
+		}, PlayFromHand{0}},
+		"not enough mana with a discount": {func() State {
+			g, _ := newGame(2, DefaultRules())
+			g.Players[0].discountSpell = true
+			g.Players[0].Hand = []CardName{DracusPyrio, Aquarius}
+			return g.setMana(0)
+		}, PlayFromHand{0}},
+		"Dracus Pyrio on a bubble": {func() State {
+			g := testGame([]CardName{Librarian, Bubublius})
+			g, _ = g.target(target{pID: 0, id: 0})
+			g.Players[0].Hand = []CardName{Aquarius}
+			return g.playCards(0, DracusPyrio)
+		}, Target{0, 0}},
+		"Meteorus without targets": {func() State {
+			return testGame([]CardName{Meteorus})
+		}, Activate{0, 0}},
+		"Dragonius attacking twice": {func() State {
+			g := testGame([]CardName{Librarian, Dragonius})
+			g, _ = g.attack(target{area: Permanent}, target{})
+			return g
+		}, DeclareAttack{Loc{Permanent, 0, 0}, 0, Loc{Wizard, 0, 0}}},
+		"dead attacker": {func() State {
+			return testGame([]CardName{Librarian, Angel}).DoDmg(0, 0, 8)
+		}, DeclareAttack{Loc{Wizard, 0, 0}, 0, Loc{Wizard, 0, 1}}},
+		"missing defender": {func() State {
+			return testGame([]CardName{Librarian})
+		}, DeclareAttack{Loc{Wizard, 0, 0}, 0, Loc{Wizard, 1, 2}}},
+		"target while not awaiting": {func() State {
+			return testGame([]CardName{Librarian})
+		}, Target{0, 0}},
+		"Extractio card not in deck": {func() State {
+			g := testGame().InitFullDeck()
+			return g.playCards(0, Extractio)
+		}, TargetDeck{Angel}},
+		"hand index out of bounds": {func() State {
+			return testGame()
+		}, PlayFromHand{3}},
+		"game over": {func() State {
+			g := testGame([]CardName{Librarian}, []CardName{Librarian})
+			g, _ = g.Apply(SetMana{0})
+			g = g.DoDmg(1, 0, 8)
+			g, _ = g.Apply(SetMana{0})
+			return g
+		}, EndTurn{}},
+	}
+
+	for name, c := range cases {
+		g := c.setup()
+		before := g.Clone()
+
+		after, err := g.Apply(c.action)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if !reflect.DeepEqual(after, before) {
+			t.Errorf("%s: failed action returned a changed state", name)
+		}
+		if !reflect.DeepEqual(g, before) {
+			t.Errorf("%s: failed action modified the original state", name)
+		}
+	}
+}
+
+func Test_Cancel(t *testing.T) {
+	g := testGame([]CardName{Librarian, Angel}, []CardName{Librarian})
+	g.Testing = false
+	g.Players[0].Hand = []CardName{Protectio, Enhancius, Extractio}
+	g.Players[0].deck = []CardName{Angel}
+	g.Mana = 6
+	g.Players[0].discountSpell = true
+
+	if _, err := g.Apply(Cancel{}); err == nil {
+		t.Error("expected error when nothing is pending")
+	}
+
+	for _, i := range []int{0, 1} {
+		card := g.Players[0].Hand[i]
+		s, err := g.Apply(PlayFromHand{i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Mana == g.Mana {
+			t.Fatalf("%s didn't cost mana", card)
+		}
+		s, err = s.Apply(Cancel{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Mana != g.Mana || !s.Players[0].discountSpell {
+			t.Errorf("%s: mana or discount wasn't refunded", card)
+		}
+		if !slices.Contains(s.Players[0].Hand, card) || len(s.Players[0].Hand) != 3 {
+			t.Errorf("%s wasn't returned to the hand: %v", card, s.Players[0].Hand)
+		}
+		if len(s.Permanents) != 0 || s.awaiting.isTrue {
+			t.Errorf("%s: perm or await left behind", card)
+		}
+	}
+
+	s, _ := g.Apply(PlayFromHand{2})
+	if _, err := s.Apply(Cancel{}); err == nil {
+		t.Error("expected Extractio to not be cancellable")
+	}
+	if slices.Contains(s.LegalActions(0), Action(Cancel{})) {
+		t.Error("expected cancel to not be legal for Extractio")
+	}
+}
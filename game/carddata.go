@@ -0,0 +1,421 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// One problem in a card file
+type CardDataErr struct {
+	Path string
+	Line int
+	Card string
+	Msg  string
+}
+
+func (e CardDataErr) Error() string {
+	where := fmt.Sprintf("line %d", e.Line)
+	if e.Path != "" {
+		where = fmt.Sprintf("%s:%d", e.Path, e.Line)
+	}
+	if e.Card == "" {
+		return fmt.Sprintf("%s: %s", where, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", where, e.Card, e.Msg)
+}
+
+// Every problem found in the card files, one per line
+type CardDataErrs []CardDataErr
+
+func (e CardDataErrs) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+var cardTypes = []string{"wizard", "perm", "instant"}
+
+// Loads the built in card data then the card files on top of it, the way
+// GetCardData does, naming the files in errors. data can be nil.
+func ReadCardFiles(data []byte, paths ...string) ([]Cdata, error) {
+	var files []cardFile
+	if data != nil {
+		files = append(files, cardFile{data: data})
+	}
+	for _, p := range paths {
+		d, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, cardFile{p, d})
+	}
+	return loadCardFiles(files)
+}
+
+type cardFile struct {
+	path string
+	data []byte
+}
+
+func loadCardFiles(files []cardFile) ([]Cdata, error) {
+	if err := validateCardFiles(files); err != nil {
+		return nil, err
+	}
+	sets := make([][]byte, len(files))
+	for i, f := range files {
+		sets[i] = f.data
+	}
+	return LoadCards(sets...)
+}
+
+// Checks card files without loading them, see CardDataErrs
+func ValidateCardData(data ...[]byte) error {
+	files := make([]cardFile, len(data))
+	for i, d := range data {
+		files[i] = cardFile{data: d}
+	}
+	return validateCardFiles(files)
+}
+
+func validateCardFiles(files []cardFile) error {
+	v := cardValidator{names: map[string]string{}, ids: map[string]string{}}
+	for _, f := range files {
+		v.file(f)
+	}
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+type cardValidator struct {
+	f     cardFile
+	errs  CardDataErrs
+	names map[string]string // where each name and id was first seen
+	ids   map[string]string
+}
+
+// A key of a JSON object, the line it's on and where its value starts
+type jsonField struct {
+	line   int
+	offset int64
+	value  json.RawMessage
+}
+
+func (v *cardValidator) report(line int, card, format string, args ...any) {
+	v.errs = append(v.errs, CardDataErr{v.f.path, line, card, fmt.Sprintf(format, args...)})
+}
+
+func (v *cardValidator) line(offset int64) int {
+	return bytes.Count(v.f.data[:min(int(offset), len(v.f.data))], []byte("\n")) + 1
+}
+
+// Offsets from the decoder point before any whitespace, commas or colons
+func (v *cardValidator) skip(offset int64) int64 {
+	for int(offset) < len(v.f.data) && strings.ContainsRune(" \t\r\n,:", rune(v.f.data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func (v *cardValidator) jsonErr(err error, base int64) {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		v.report(v.line(base+syntax.Offset), "", "%s", syntax.Error())
+	case errors.As(err, &typ):
+		v.report(v.line(base+typ.Offset), "", "%s", typ.Error())
+	default:
+		v.report(v.line(base), "", "%s", err.Error())
+	}
+}
+
+// The keys of the object starting at base in the file, in order
+func (v *cardValidator) object(raw json.RawMessage, base int64) ([]string, map[string]jsonField, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		v.report(v.line(base), "", "Expected an object")
+		return nil, nil, false
+	}
+
+	var keys []string
+	fields := map[string]jsonField{}
+	for dec.More() {
+		off := v.skip(base + dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			v.jsonErr(err, base)
+			return nil, nil, false
+		}
+		key := tok.(string)
+		valueOff := v.skip(base + dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			v.jsonErr(err, base)
+			return nil, nil, false
+		}
+		keys = append(keys, key)
+		fields[key] = jsonField{v.line(off), valueOff, value}
+	}
+	return keys, fields, true
+}
+
+func (v *cardValidator) file(f cardFile) {
+	v.f = f
+	dec := json.NewDecoder(bytes.NewReader(f.data))
+	tok, err := dec.Token()
+	if err != nil {
+		v.jsonErr(err, 0)
+		return
+	}
+
+	switch tok {
+	case json.Delim('['):
+		v.cardList(dec, 0)
+	case json.Delim('{'):
+		var raw json.RawMessage
+		if err := json.Unmarshal(f.data, &raw); err != nil {
+			v.jsonErr(err, 0)
+			return
+		}
+		v.setFile(raw)
+	default:
+		v.report(1, "", "Expected a list of cards or a card set")
+	}
+}
+
+func (v *cardValidator) setFile(raw json.RawMessage) {
+	keys, fields, ok := v.object(raw, 0)
+	if !ok {
+		return
+	}
+	for _, k := range keys {
+		if k != "set" && k != "cards" {
+			v.report(fields[k].line, "", "Unknown field %q", k)
+		}
+	}
+
+	set, ok := fields["set"]
+	if !ok {
+		v.report(1, "", "Card set without a set")
+	} else {
+		var meta CardSet
+		if err := json.Unmarshal(set.value, &meta); err != nil {
+			v.report(set.line, "", "%s", err.Error())
+		} else if meta.ID == "" {
+			v.report(set.line, "", "Card set without an id")
+		} else if !slices.Contains(setKinds, meta.Kind) {
+			v.report(set.line, "", "Card set %q has unknown kind %q", meta.ID, meta.Kind)
+		}
+	}
+
+	list, ok := fields["cards"]
+	if !ok {
+		v.report(1, "", "Card set without cards")
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(list.value))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		v.report(list.line, "", "Expected a list of cards")
+		return
+	}
+	v.cardList(dec, list.offset)
+}
+
+func (v *cardValidator) cardList(dec *json.Decoder, base int64) {
+	for dec.More() {
+		off := v.skip(base + dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			v.jsonErr(err, off)
+			return
+		}
+		v.card(raw, off)
+	}
+}
+
+// The json keys of Cdata
+var cardKeys = []string{
+	"id", "set", "type", "name", "desc", "hp", "cost", "atk1", "atk2",
+	"rarity", "flavor", "keywords", "effects",
+}
+
+func (v *cardValidator) card(raw json.RawMessage, base int64) {
+	keys, fields, ok := v.object(raw, base)
+	if !ok {
+		return
+	}
+	start := v.line(base)
+
+	var c Cdata
+	if err := json.Unmarshal(raw, &c); err != nil {
+		line := start
+		var typ *json.UnmarshalTypeError
+		if errors.As(err, &typ) {
+			line = v.line(base + typ.Offset)
+		}
+		v.report(line, c.Name, "%s", err.Error())
+		return
+	}
+	name := c.Name
+	fieldLine := func(key string) int {
+		if f, ok := fields[key]; ok {
+			return f.line
+		}
+		return start
+	}
+
+	for _, k := range keys {
+		if !slices.Contains(cardKeys, k) {
+			v.report(fields[k].line, name, "Unknown field %q", k)
+		}
+	}
+	if c.Rarity != "" && !slices.Contains(rarities, c.Rarity) {
+		v.report(fieldLine("rarity"), name, "Unknown rarity %q", c.Rarity)
+	}
+
+	if c.Name == "" {
+		v.report(start, "", "Card without a name")
+	} else if where, ok := v.names[strings.ToLower(c.Name)]; ok {
+		v.report(fieldLine("name"), name, "Duplicate name, first used %s", where)
+	} else {
+		v.names[strings.ToLower(c.Name)] = v.where(fieldLine("name"))
+	}
+	id := c.ID
+	if id == "" {
+		id = cardID(c.Name)
+	}
+	if where, ok := v.ids[id]; ok && id != "" {
+		v.report(fieldLine("id"), name, "Duplicate id %q, first used %s", id, where)
+	} else if id != "" {
+		v.ids[id] = v.where(fieldLine("id"))
+	}
+
+	switch {
+	case !slices.Contains(cardTypes, c.Type):
+		v.report(fieldLine("type"), name, "Unknown type %q", c.Type)
+	case c.Type == "wizard":
+		if c.Hp < 1 {
+			v.report(fieldLine("hp"), name, "Wizards need hp above 0")
+		}
+		if _, ok := fields["cost"]; ok {
+			v.report(fieldLine("cost"), name, "Wizards don't have a cost")
+		}
+		for _, atk := range []string{"atk1", "atk2"} {
+			var a Attack
+			if f, ok := fields[atk]; !ok || json.Unmarshal(f.value, &a) != nil || a.Name == "" {
+				v.report(fieldLine(atk), name, "Missing attack %s", atk)
+			}
+		}
+	default:
+		// Older files put the cost in hp, that's still read
+		_, hasHp := fields["hp"]
+		_, hasCost := fields["cost"]
+		if hasHp && hasCost {
+			v.report(fieldLine("hp"), name, "Only wizards have hp, use cost")
+		}
+		if c.Cost < 0 {
+			v.report(fieldLine("cost"), name, "Cost can't be negative")
+		}
+		for _, atk := range []string{"atk1", "atk2"} {
+			if _, ok := fields[atk]; ok {
+				v.report(fieldLine(atk), name, "Only wizards have attacks")
+			}
+		}
+	}
+
+	line := fieldLine("effects")
+	e := c.Effects
+	switch c.Type {
+	case "wizard":
+		if len(e.Atk0) == 0 {
+			v.report(line, name, "Missing effects for atk1")
+		}
+		if len(e.Atk1) == 0 {
+			v.report(line, name, "Missing effects for atk2")
+		}
+	case "instant":
+		if len(e.Play) == 0 {
+			v.report(line, name, "Missing play effects")
+		}
+	case "perm":
+		if len(e.Play) == 0 && len(e.Activate) == 0 && e.Passive == "" {
+			v.report(line, name, "Missing effects, perms need play, activate or passive")
+		}
+	}
+	// Problems inside effects are reported on their own line
+	var effects map[string]jsonField
+	if f, ok := fields["effects"]; ok && bytes.HasPrefix(f.value, []byte("{")) {
+		_, effects, _ = v.object(f.value, f.offset)
+	}
+	effectLine := func(key string) int {
+		if f, ok := effects[key]; ok {
+			return f.line
+		}
+		return line
+	}
+	if e.Passive != "" && permTriggers[e.Passive] == nil {
+		v.report(effectLine("passive"), name, "Unknown passive %q", e.Passive)
+	}
+	for _, key := range []string{"atk1", "atk2", "play", "activate"} {
+		if f, ok := effects[key]; ok {
+			v.effects(f, name)
+		}
+	}
+	if n := e.Needs; n != "" && conditions[n] == nil {
+		v.report(effectLine("needs"), name, "Unknown condition %q", n)
+	}
+}
+
+// Checks each effect in the list at f and the effects they run after
+func (v *cardValidator) effects(f jsonField, card string) {
+	dec := json.NewDecoder(bytes.NewReader(f.value))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return
+	}
+	rules := DefaultRules()
+	for dec.More() {
+		off := v.skip(f.offset + dec.InputOffset())
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return
+		}
+		// Already decoded with the card, so this can't fail
+		var e Effect
+		json.Unmarshal(raw, &e)
+		line := v.line(off)
+
+		if _, ok := effectOps[e.Op]; !ok {
+			v.report(line, card, "Unknown effect %q", e.Op)
+		}
+		if e.If != "" && conditions[e.If] == nil {
+			v.report(line, card, "Unknown condition %q", e.If)
+		}
+		if _, ok := rules.value(e.Rule); e.Rule != "" && e.Rule != "attack" && !ok {
+			v.report(line, card, "Unknown rule %q", e.Rule)
+		}
+		if e.Op == "status" && !slices.Contains(statusNames[:], e.Status) {
+			v.report(line, card, "Unknown status %q", e.Status)
+		}
+		if len(e.Then) > 0 {
+			if _, fields, ok := v.object(raw, off); ok {
+				v.effects(fields["then"], card)
+			}
+		}
+	}
+}
+
+func (v *cardValidator) where(line int) string {
+	if v.f.path == "" {
+		return fmt.Sprintf("on line %d", line)
+	}
+	return fmt.Sprintf("at %s:%d", v.f.path, line)
+}
@@ -0,0 +1,106 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var badCards = []byte(`[
+	{"name": "Librarian", "type": "wizard", "hp": 8,
+		"atk1": {"name": "draw1", "dmg": 1},
+		"atk2": {"name": "dmgPerCard", "dmg": 0}},
+	{"name": "Sorcerer", "type": "wizzard", "hp": 5},
+	{"name": "Golem", "type": "wizard", "hp": 9, "cost": 2,
+		"atk1": {"name": "smash", "dmg": 2}},
+	{"name": "Fireball", "type": "instant", "hp": 1, "cost": 3, "rarity": "mythic",
+		"atk1": {"name": "burn", "dmg": 3},
+		"effects": {"play": [{"op": "explode"}]}},
+	{"name": "Trap", "type": "perm", "cost": 2,
+		"effects": {
+			"play": [{"op": "draw"},
+				{"op": "await", "target": "wizard", "then": [
+					{"op": "hit", "if": "lucky"}]}],
+			"activate": [{"op": "status", "status": "frozen"}],
+			"passive": "spring"}}
+]`)
+
+func Test_ValidateCardData(t *testing.T) {
+	if err := ValidateCardData(data); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ValidateCardData(data, badCards)
+	var errs CardDataErrs
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected CardDataErrs got %v", err)
+	}
+	expected := []CardDataErr{
+		{Line: 2, Card: "Librarian", Msg: "Duplicate name, first used on line 4"},
+		{Line: 2, Card: "Librarian", Msg: `Duplicate id "librarian", first used on line 3`},
+		{Line: 2, Card: "Librarian", Msg: "Missing effects for atk1"},
+		{Line: 2, Card: "Librarian", Msg: "Missing effects for atk2"},
+		{Line: 5, Card: "Sorcerer", Msg: `Unknown type "wizzard"`},
+		{Line: 6, Card: "Golem", Msg: "Wizards don't have a cost"},
+		{Line: 6, Card: "Golem", Msg: "Missing attack atk2"},
+		{Line: 6, Card: "Golem", Msg: "Missing effects for atk1"},
+		{Line: 6, Card: "Golem", Msg: "Missing effects for atk2"},
+		{Line: 8, Card: "Fireball", Msg: `Unknown rarity "mythic"`},
+		{Line: 8, Card: "Fireball", Msg: "Only wizards have hp, use cost"},
+		{Line: 9, Card: "Fireball", Msg: "Only wizards have attacks"},
+		{Line: 10, Card: "Fireball", Msg: `Unknown effect "explode"`},
+		{Line: 17, Card: "Trap", Msg: `Unknown passive "spring"`},
+		{Line: 15, Card: "Trap", Msg: `Unknown condition "lucky"`},
+		{Line: 16, Card: "Trap", Msg: `Unknown status "frozen"`},
+	}
+	if !reflect.DeepEqual([]CardDataErr(errs), expected) {
+		t.Errorf("unexpected errors\n%v", err)
+	}
+
+	if _, err := GetCardData([]byte(`[{"name": "Broken", "type": "perm", "hp": "two"}]`)); err == nil {
+		t.Error("expected bad json to fail without panicking")
+	}
+	path := t.TempDir() + "/cards.json"
+	os.WriteFile(path, badCards, 0644)
+	if _, err := ReadCardFiles(nil, path); err == nil || !strings.Contains(err.Error(), path+":5: Sorcerer") {
+		t.Errorf("expected errors to name the file got %v", err)
+	}
+
+	// Expansions are loaded on top of the built in cards
+	os.WriteFile(path, expansion, 0644)
+	added, err := ReadCardFiles(data, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := LookupCard(added, Librarian); len(added) != len(cards)+2 || !ok || c.Name != "Librarian" {
+		t.Errorf("expected the core cards and the expansion, got %d cards", len(added))
+	}
+}
+
+func Test_CardCost(t *testing.T) {
+	if c, _ := LookupCard(cards, PyrusBalio); c.Cost != 1 || c.Hp != 0 {
+		t.Errorf("expected Pyrus Balio to cost 1 got cost %d hp %d", c.Cost, c.Hp)
+	}
+	if p := CardFromName(cards, Aquarius); p.getCost() != 3 {
+		t.Errorf("expected Aquarius to cost 3 got %d", p.getCost())
+	}
+	if c, _ := LookupCard(cards, Librarian); c.Cost != 0 || c.Hp != 8 {
+		t.Error("expected wizards to keep their hp")
+	}
+
+	// Files from before cost was added
+	old := bytes.ReplaceAll(data, []byte(`"cost":`), []byte(`"hp":`))
+	if err := ValidateCardData(old); err != nil {
+		t.Fatal(err)
+	}
+	oldCards, err := LoadCards(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(oldCards, cards) {
+		t.Error("expected old card files to load the same cards")
+	}
+}
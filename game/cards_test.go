@@ -5,11 +5,64 @@ import (
 	"fmt"
 	"testing"
 	"math/rand"
+	"slices"
 )
 
 //go:embed cards.json
 var data []byte
-var cards []Cdata = GetCardData(data)
+var cards []Cdata = mustLoadCards(data)
+
+// Card effects come from the card data so test games need it
+func newTestGame(players int) (State, error) {
+	g, err := NewTestGame(players)
+	return g.SetCardData(cards), err
+}
+
+func newGame(players int, rules GameRules) (State, error) {
+	g, err := NewGame(players, rules)
+	return g.SetCardData(cards), err
+}
+
+// GetCardData can't be used before the package's init has run
+func mustLoadCards(data ...[]byte) []Cdata {
+	cards, err := LoadCards(data...)
+	if err != nil {
+		panic(err)
+	}
+	return cards
+}
+
+// testGame is a test game with fields[p] played for player p, and always
+// at least two players
+func testGame(fields ...[]CardName) State {
+	g, _ := newTestGame(max(len(fields), 2))
+	for p, names := range fields {
+		g = g.playCards(p, names...)
+	}
+	return g
+}
+
+// deckGame is an unstarted game where every deck is the wizards followed by
+// one of each spell
+func deckGame(players int, rules GameRules, seed uint64, wizards ...CardName) State {
+	g, _ := newGame(players, rules)
+	g = g.SetSeed(seed)
+	for p := range players {
+		g.Players[p].deck = slices.Clone(wizards)
+		for c := PyrusBalio; c <= Extractio; c++ {
+			g.Players[p].deck = append(g.Players[p].deck, c)
+		}
+	}
+	return g
+}
+
+func seededGame(seed uint64) State {
+	return deckGame(2, DefaultRules(), seed, Librarian, Angel, Pyromancer).Start(cards)
+}
+
+func phaseGame(rules GameRules, wizards ...CardName) State {
+	return deckGame(2, rules, 9, wizards...).Start(cards)
+}
 
 func (g State) doAtk(n int) State {
 	if n != 0 && n != 1 {
@@ -94,7 +147,7 @@ func Test_LenPerm(t *testing.T) {
 }
 
 func Test_Poo(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g, err := g.play(playerID(0), CardFromName(cards, Dragonius))
 	if err != nil {
 		t.Error(err)
@@ -102,7 +155,7 @@ func Test_Poo(t *testing.T) {
 }
 
 func Test_NumPerms(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Dragonius, Dragonius, Librarian, Aquarius)
 	g = g.playCards(1, Dragonius, Librarian)
 
@@ -136,7 +189,8 @@ func Test_RemovePerm(t *testing.T) {
 }
 
 func Test_Mana(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
+	g = g.playCards(0, Librarian).playCards(1, Librarian)
 	expect := func(expected int) {
 		if g.Mana != expected {
 			t.Errorf("Expected mana %d got %d", expected, g.Mana)
@@ -153,7 +207,7 @@ func Test_Mana(t *testing.T) {
 }
 
 func Test_Librarian(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Librarian)
 
 	g = g.InitFullDeck()
@@ -172,7 +226,7 @@ func Test_Librarian(t *testing.T) {
 }
 
 func Test_Angel(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
 	g = g.playCards(0, Angel, Librarian)
 
@@ -194,7 +248,7 @@ func Test_Angel(t *testing.T) {
 }
 
 func Test_Magician(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
 	g = g.playCards(0, Magician, Librarian)
 
@@ -218,7 +272,7 @@ func Test_Magician(t *testing.T) {
 }
 
 func Test_Pyromancer(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Pyromancer, Librarian, Librarian)
 	g = g.playCards(1, Librarian, Librarian)
 
@@ -238,9 +292,9 @@ func Test_Pyromancer(t *testing.T) {
 }
 
 func Test_Shieldmancer(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
-	g = g.playCards(0, Shieldmancer, Librarian)
+	g = g.playCards(0, Shieldmancer, Librarian).playCards(1, Librarian)
 
 	g = g.doAtk(0)
 	hp := g.CardHp(0, 1)
@@ -261,9 +315,9 @@ func Test_Shieldmancer(t *testing.T) {
 }
 
 func Test_Conjurer(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
-	g = g.playCards(0, Conjurer, Librarian)
+	g = g.playCards(0, Conjurer, Librarian).playCards(1, Librarian)
 
 	g0 := g
 	mana := g0.Mana
@@ -273,7 +327,7 @@ func Test_Conjurer(t *testing.T) {
 		t.Errorf("expected mana %d got %d", mana+2, newMana)
 	}
 
-	g1, _ := NewGame(2)
+	g1, _ := newGame(2, DefaultRules())
 	g1, _ = g1.play(playerID(0), CardFromName(cards, Conjurer))
 	g1, _ = g1.play(playerID(0), CardFromName(cards, Librarian))
 
@@ -296,7 +350,7 @@ func Test_Conjurer(t *testing.T) {
 }
 
 func Test_Mortician(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
 	g = g.playCards(0, Mortician, Librarian, Librarian)
 	g = g.playCards(1, Librarian)
@@ -316,7 +370,7 @@ func Test_Mortician(t *testing.T) {
 }
 
 func Test_MindMage(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, MindMage, Shieldmancer)
 	g, _ = g.attack(target{pID: 0, id: 1, atkNum: 0},
 		target{pID: 0, id: 1})
@@ -339,7 +393,7 @@ func Test_MindMage(t *testing.T) {
 }
 
 func Test_Bloodeater(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Bloodeater, Librarian)
 
 	g = g.doAtk(0)
@@ -363,7 +417,7 @@ func Test_Bloodeater(t *testing.T) {
 }
 
 func Test_SpellsCostMana(t *testing.T) {
-	g, _ := NewGame(2)
+	g, _ := newGame(2, DefaultRules())
 	g = g.setMana(0)
 	g, err := g.play(playerID(0), CardFromName(cards, PyrusBalio))
 	if err == nil {
@@ -381,7 +435,7 @@ func Test_SpellsCostMana(t *testing.T) {
 }
 
 func Test_PyrusBalio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, PyrusBalio)
 
 	g, _ = g.target(target{pID: 0, id: 0})
@@ -389,20 +443,20 @@ func Test_PyrusBalio(t *testing.T) {
 }
 
 func Test_Protectio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Protectio)
 	g, err := g.target(target{pID: 0, id: 0})
 	if err != nil {
 		t.Error(err)
 	}
 
-	if g.Field[0][0].protected == false {
+	if !g.Field[0][0].HasStatus(Protected) {
 		t.Error("expected wizard to be protected")
 	}
 }
 
 func Test_Mortius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Bloodeater, Librarian, Mortius)
 	g, _ = g.target(target{pID: 0, id: 1})
 	g = g.doAtk(1).doAtk(1)
@@ -413,7 +467,7 @@ func Test_Mortius(t *testing.T) {
 }
 
 func Test_Enhancius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Shieldmancer, Enhancius)
 	g, _ = g.target(target{pID: 0, id: 0})
 	g = g.doAtk(0)
@@ -421,7 +475,7 @@ func Test_Enhancius(t *testing.T) {
 }
 
 func Test_Dragonius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Dragonius)
 
 	d, err := g.cardFromTarget(target{pID: 0, id: 0, area: Permanent})
@@ -433,15 +487,14 @@ func Test_Dragonius(t *testing.T) {
 		t.Errorf("Dragon card from target gives %v", d.CName)
 	}
 
+	// The dragon's attack hits the defender straight away
 	g, err = g.attack(target{pID: 0, id: 0, area: Permanent},
 		target{pID: 0, id: 0})
 	if err != nil {
 		t.Error(err)
 	}
-
-	g, err = g.target(target{pID: 0, id: 0})
-	if err != nil {
-		t.Error(err)
+	if g.awaiting.isTrue {
+		t.Error("expected the dragon's attack to not wait for a target")
 	}
 	if hp := g.Field[0][0].HP; hp != 5 {
 		t.Errorf("expected.HP 5 got %d", hp)
@@ -458,7 +511,7 @@ func Test_Dragonius(t *testing.T) {
 		t.Error(err)
 	}
 
-	if d.HP != 2 {
+	if d := g.Dragons[0][0]; d.HP != 2 {
 		t.Errorf("Expected HP 2 Got %d", d.HP)
 	}
 
@@ -474,8 +527,22 @@ func Test_Dragonius(t *testing.T) {
 	}
 }
 
+func Test_FailedStrikeKeepsState(t *testing.T) {
+	custom := slices.Clone(cards)
+	custom[Librarian-1].Effects.Atk0 = []Effect{{Op: "hit"}, {Op: "missing"}}
+	g, _ := newTestGame(2)
+	g = g.SetCardData(custom).playCards(0, Librarian).playCards(1, Angel)
+
+	if _, err := g.strike(target{pID: 0, id: 0}, target{pID: 1, id: 0}); err == nil {
+		t.Fatal("expected the unknown effect to fail")
+	}
+	if hp := g.Field[1][0].HP; hp != 8 {
+		t.Errorf("expected the failed attack to deal no damage, hp is %d", hp)
+	}
+}
+
 func Test_Cancelio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Dragonius, Cancelio)
 	g, _ = g.target(target{pID: 0, id: 0, area: Permanent})
 	if _, ok := g.Permanents[PermTarget{0, 0}]; ok == true {
@@ -485,14 +552,14 @@ func Test_Cancelio(t *testing.T) {
 }
 
 func Test_Dralio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.InitFullDeck()
 	g = g.playCards(0, Dralio)
 	g.checkHandSize(t, 2)
 }
 
 func Test_AngeliDustio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian)
 	g = g.DoDmg(0, 0, 1)
 	g = g.playCards(0, AngeliDustio)
@@ -501,7 +568,7 @@ func Test_AngeliDustio(t *testing.T) {
 }
 
 func Test_Conjorius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Librarian, Conjorius)
 	g = g.DoDmg(0, 0, 8)
 	g = g.DoDmg(0, 1, 8)
@@ -512,7 +579,7 @@ func Test_Conjorius(t *testing.T) {
 }
 
 func Test_Vitalius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, MindMage, Vitalius)
 	g, _ = g.target(target{pID: 0, id: 0})
 	g.checkHpIs(t, 10)
@@ -523,10 +590,10 @@ func Test_Vitalius(t *testing.T) {
 }
 
 func Test_Librarius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.InitFullDeck()
 
-	gWithLib, _ := NewTestGame(2)
+	gWithLib, _ := newTestGame(2)
 	gWithLib = gWithLib.InitFullDeck()
 	gWithLib = gWithLib.playCards(0, Librarius)
 
@@ -542,8 +609,8 @@ func Test_Librarius(t *testing.T) {
 }
 
 func Test_Aquarius(t *testing.T) {
-	g, _ := NewTestGame(2)
-	g = g.playCards(0, Aquarius)
+	g, _ := newTestGame(2)
+	g = g.playCards(0, Librarian, Aquarius).playCards(1, Librarian)
 	for i := 0; i < 15; i++ {
 		g, _ = g.endTurn()
 	}
@@ -553,7 +620,7 @@ func Test_Aquarius(t *testing.T) {
 }
 
 func Test_Bubublius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Bubublius)
 	g, e := g.target(target{pID: 0, id: 0})
 	if e != nil {
@@ -568,7 +635,7 @@ func Test_Bubublius(t *testing.T) {
 }
 
 func Test_Meteorus(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Meteorus)
 	g, _ = g.activatePerm(PermTarget{0, 0})
 	g.checkHpIs(t, 7)
@@ -577,7 +644,7 @@ func Test_Meteorus(t *testing.T) {
 }
 
 func Test_Armorius(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Librarian, Armorius)
 	g, _ = g.target(target{pID: 0, id: 0})
 	g = g.DoDmg(0, 0, 1)
@@ -587,7 +654,7 @@ func Test_Armorius(t *testing.T) {
 }
 
 func Test_DracusPyrio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.InitFullDeck()
 	g = g.playCards(0, Librarian)
 	g, err := g.play(0, Instant{CName: DracusPyrio})
@@ -605,7 +672,7 @@ func Test_DracusPyrio(t *testing.T) {
 }
 
 func Test_Retrievio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.playCards(0, Aquarius, Retrievio)
 	g, err := g.target(target{pID: 0, id: 0, area: Permanent})
 	if err != nil {
@@ -626,7 +693,7 @@ func Test_Retrievio(t *testing.T) {
 }
 
 func Test_Extractio(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 	g = g.InitFullDeck()
 	g = g.playCards(0, Extractio)
 
@@ -663,7 +730,7 @@ func Test_IsWizard(t *testing.T) {
 }
 
 func Test_Shuffle(t *testing.T) {
-	g, _ := NewTestGame(2)
+	g, _ := newTestGame(2)
 
 	g.Players[0].deck = []CardName{} 
 	for i := 0; i < 3; i++ {
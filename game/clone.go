@@ -0,0 +1,43 @@
+package game
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+)
+
+// Keeps the capacity of s so that card pointers into the copy stay valid
+// while it grows, like the original.
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	res := make([]T, len(s), cap(s))
+	copy(res, s)
+	return res
+}
+
+func (p Player) Clone() Player {
+	p.Hand = cloneSlice(p.Hand)
+	p.Discard = cloneSlice(p.Discard)
+	p.deck = cloneSlice(p.deck)
+	return p
+}
+
+// Clone returns a State that shares no mutable data with s. Card data
+// passed to Start or SetCardData is read only and is not copied.
+func (s State) Clone() State {
+	for p := range s.Players {
+		s.Players[p] = s.Players[p].Clone()
+	}
+	for p := range s.Field {
+		s.Field[p] = cloneSlice(s.Field[p])
+		s.Dragons[p] = cloneSlice(s.Dragons[p])
+	}
+	s.Permanents = maps.Clone(s.Permanents)
+	s.Output.Messages = slices.Clip(s.Output.Messages)
+	if s.Logs != nil {
+		s.Logs = bytes.NewBuffer(slices.Clone(s.Logs.Bytes()))
+	}
+	return s
+}
@@ -0,0 +1,28 @@
+package game
+
+import "testing"
+
+func Test_Clone(t *testing.T) {
+	g := testGame([]CardName{Librarian, Angel, Aquarius}).InitFullDeck()
+	g = g.drawCards(0, 2)
+
+	c := g.Clone()
+	c = c.DoDmg(0, 0, 3)
+	c, _ = c.removePerm(PermTarget{0, 0})
+	c = c.drawCards(0, 1)
+	c.Players[0].Hand[0] = Dragonius
+
+	g.checkHpIs(t, 8)
+	if _, ok := g.Permanents[PermTarget{0, 0}]; !ok {
+		t.Error("removing a perm from the clone removed it from the original")
+	}
+	if g.handSize() != 2 || g.Players[0].Hand[0] != Librarian {
+		t.Errorf("original hand changed: %v", g.Players[0].Hand)
+	}
+	if len(g.Players[0].deck) != 28 || len(g.Players[0].Discard) != 0 {
+		t.Error("original deck or discard changed")
+	}
+	if len(c.Output.Messages) == len(g.Output.Messages) {
+		t.Error("expected clone to have its own log")
+	}
+}
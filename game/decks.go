@@ -8,12 +8,6 @@ import (
 	"strings"
 )
 
-const (
-	MaxDeckLength = 20
-	MaxWizards    = 3
-	MaxCopies     = 4
-)
-
 type DeckError struct{ msg string }
 
 func (d DeckError) Error() string {
@@ -23,41 +17,43 @@ func (d DeckError) Error() string {
 var (
 	DeckLineErr           = DeckError{"Must be 2 numbers on each line"}
 	DeckFormatErr         = DeckError{"Format is wrong"}
-	MaxCardCopiesErr      = DeckError{fmt.Sprintf("Over max copies per card, %d", MaxCopies)}
-	MaxDeckErr            = DeckError{fmt.Sprintf("Over deck limit, %d", MaxDeckLength)}
-	InvalidCardErr        = DeckError{"Invalid card number"}
+	InvalidCardErr        = DeckError{"Invalid card"}
 	LimitOneEachWizardErr = DeckError{"Limit one of each wizards"}
 )
 
+// Each line is an amount and a card ID, like "2 pyrus-balio". Card
+// numbers from older deck files are still accepted.
 func ParseDeck(data []byte) (map[int]int, error) {
-	d := bytes.NewBuffer(data).String()
+	d := string(bytes.TrimRight(data, "\x00"))
 
 	deck := make(map[int]int)
 
-	lines := strings.Split(d, "\n")
-	// dont know why we have to subtract 2 but we do
-	for i := 0; i < len(lines) - 1; i++ {
-		nums := strings.Split(lines[i], " ")
-		if len(nums) != 2 {
-			return deck, DeckFormatErr 
+	for _, line := range strings.Split(d, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return deck, DeckFormatErr
 		}
 
-		amount, err := strconv.Atoi(nums[0])
+		amount, err := strconv.Atoi(fields[0])
 		if err != nil {
-			return deck, DeckFormatErr 
+			return deck, DeckFormatErr
 		}
 
-		id, err := strconv.Atoi(nums[1])
+		name, err := ParseCardName(fields[1])
 		if err != nil {
-			return deck, DeckFormatErr 
+			return deck, err
 		}
-		deck[id] = amount
+		deck[int(name)] += amount
 	}
 	return deck, nil
 }
 
 func isWizard(cards []Cdata, id int) bool {
-	return cards[id - 1].Type == "wizard"
+	c, _ := LookupCard(cards, CardName(id))
+	return c.Type == "wizard"
 }
 
 type DeckEntry struct {
@@ -83,21 +79,30 @@ func SortedDeckList(cards []Cdata, m map[int]int) (list []DeckEntry) {
 	return
 }
 
-func ValidateDeck(cards []Cdata, deck map[int]int) error {
+func ValidateDeck(rules GameRules, cards []Cdata, deck map[int]int) error {
+	format, ok := LookupFormat(rules.Format)
+	if !ok {
+		return DeckError{fmt.Sprintf("Unknown format %q", rules.Format)}
+	}
+
 	total := 0
 	totalWizards := 0
 	for id, amount := range deck {
-		if id < 0 || id >= len(cards) {
+		c, ok := LookupCard(cards, CardName(id))
+		if !ok {
 			return InvalidCardErr
 		}
+		if !format.Allows(c) {
+			return DeckError{fmt.Sprintf("%s isn't legal in %s", c.Name, format.Name)}
+		}
 
-		if amount > 4 {
-			return MaxCardCopiesErr
+		if amount > rules.MaxCopies {
+			return DeckError{fmt.Sprintf("Over max copies per card, %d", rules.MaxCopies)}
 		}
 
 		total += amount
-		if total > MaxDeckLength {
-			return MaxDeckErr
+		if total > rules.MaxDeckLength {
+			return DeckError{fmt.Sprintf("Over deck limit, %d", rules.MaxDeckLength)}
 		}
 
 		if isWizard(cards, id) {
@@ -107,8 +112,8 @@ func ValidateDeck(cards []Cdata, deck map[int]int) error {
 			totalWizards++
 		}
 	}
-	if totalWizards != MaxWizards {
-		return DeckError{fmt.Sprintf("Expected 3 wizards got %d", totalWizards)} 
+	if totalWizards != rules.MaxWizards {
+		return DeckError{fmt.Sprintf("Expected %d wizards got %d", rules.MaxWizards, totalWizards)} 
 	}
 	return nil
 }
@@ -116,7 +121,7 @@ func ValidateDeck(cards []Cdata, deck map[int]int) error {
 func EntriesToBytes(entries []DeckEntry) []byte {
 	s := ""
 	for _, e := range entries {
-		s += fmt.Sprintf("%d %d\n", e.Amount, e.ID)	
+		s += fmt.Sprintf("%d %s\n", e.Amount, CardName(e.ID).ID())
 	}
 	return []byte(s)
 }
@@ -129,7 +134,7 @@ func SearchSortedEntries(entries []DeckEntry, id int) (int, bool) {
 
 func (s State) SetDeckFromMap(p int, cards []Cdata, d map[int]int) (State, error) {
 	s.Players[p].deck = []CardName{}
-	if err := ValidateDeck(cards, d); err != nil {
+	if err := ValidateDeck(s.Rules, cards, d); err != nil {
 		return s, err
 	}
 
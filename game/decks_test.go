@@ -0,0 +1,64 @@
+package game
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ValidateDeckRules(t *testing.T) {
+	deck := map[int]int{
+		int(Librarian):  1,
+		int(Angel):      1,
+		int(Mortician):  1,
+		int(PyrusBalio): 3,
+	}
+	if err := ValidateDeck(DefaultRules(), cards, deck); err != nil {
+		t.Error(err)
+	}
+
+	r := DefaultRules()
+	r.MaxCopies = 2
+	if err := ValidateDeck(r, cards, deck); err == nil {
+		t.Error("expected too many copies")
+	}
+}
+
+func Test_DeckIDs(t *testing.T) {
+	deck, err := ParseDeck([]byte("1 librarian\n1 2\n1 angel\n4 pyrus-balio\n2 11"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck[int(Magician)] != 1 || deck[int(PyrusBalio)] != 6 || len(deck) != 4 {
+		t.Errorf("unexpected deck %v", deck)
+	}
+	if _, err := ParseDeck([]byte("1 not-a-card\n")); err == nil {
+		t.Error("expected unknown cards to fail")
+	}
+
+	text := EntriesToBytes(SortedDeckList(cards, deck))
+	if string(text) != "1 librarian\n1 magician\n1 angel\n6 pyrus-balio\n" {
+		t.Errorf("unexpected deck file %q", text)
+	}
+	parsed, err := ParseDeck(text)
+	if err != nil || !reflect.DeepEqual(parsed, deck) {
+		t.Error("expected the deck file to round trip")
+	}
+
+	g := seededGame(3)
+	saved, err := g.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), `"librarian"`) {
+		t.Error("expected cards to be saved by id")
+	}
+	old := strings.ReplaceAll(string(saved), `"librarian"`, "1")
+	loaded, err := Load([]byte(old), cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Field, g.Field) {
+		t.Error("expected numeric card names to still load")
+	}
+}
@@ -0,0 +1,51 @@
+package game
+
+import "slices"
+
+func (c Card) Alive() bool {
+	return c.HP > 0
+}
+
+func (s State) discard(p playerID, cards ...CardName) State {
+	s.Players[p].Discard = append(s.Players[p].Discard, cards...)
+	return s
+}
+
+// Takes the most recent copy of c back out of p's discard pile
+func (s State) undiscard(p playerID, c CardName) State {
+	d := s.Players[p].Discard
+	for i := len(d) - 1; i >= 0; i-- {
+		if d[i] == c {
+			s.Players[p].Discard = slices.Delete(d, i, i+1)
+			break
+		}
+	}
+	return s
+}
+
+// Wizards that die go to the discard pile but stay on the field so they
+// can be revived. Dragons that die take their Dragonius with them.
+func (s State) die(loc Loc, c *Card) State {
+	s.emit(WizardDied{loc, c.CName})
+	if loc.Area == Permanent {
+		s, _ = s.removePerm(PermTarget{playerID(loc.PID), loc.ID})
+		return s
+	}
+	return s.discard(playerID(loc.PID), c.CName)
+}
+
+func (s State) kill(c *Card) State {
+	return s.doRawDmg(c, c.HP)
+}
+
+func (s State) revive(c *Card) State {
+	loc := s.locOf(c)
+	if !c.Alive() {
+		s = s.undiscard(playerID(loc.PID), c.CName)
+	}
+	if healed := s.Rules.MaxHp - c.HP; healed > 0 {
+		c.HP = s.Rules.MaxHp
+		s.emit(Healed{loc, c.CName, healed})
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_Discard(t *testing.T) {
+	g := testGame([]CardName{Librarian, Angel, PyrusBalio}, []CardName{Dragonius})
+	g, _ = g.target(target{pID: 0, id: 0})
+	g = g.playCards(0, Cancelio)
+	g, _ = g.target(target{pID: 1, id: 0, area: Permanent})
+
+	if d := g.Players[0].Discard; !slices.Equal(d, []CardName{PyrusBalio, Cancelio}) {
+		t.Errorf("expected spent instants in discard, got %v", d)
+	}
+	if d := g.Players[1].Discard; !slices.Equal(d, []CardName{Dragonius}) {
+		t.Errorf("expected removed perm in owner's discard, got %v", d)
+	}
+	if g.Dragons[1][0] != (Card{}) {
+		t.Error("expected dragon to leave with its perm")
+	}
+
+	g = g.DoDmg(0, 0, 8)
+	if d := g.Players[0].Discard; d[len(d)-1] != Librarian {
+		t.Errorf("expected dead wizard in discard, got %v", d)
+	}
+
+	g, _ = g.attack(target{pID: 0, id: 1, atkNum: 1}, target{pID: 0, id: 0})
+	g, _ = g.target(target{pID: 0, id: 0})
+	if d := g.Players[0].Discard; !slices.Equal(d, []CardName{PyrusBalio, Cancelio, Angel}) {
+		t.Errorf("expected revived wizard to leave discard, got %v", d)
+	}
+
+	if _, err := g.attack(target{pID: 0, id: 1}, target{pID: 0, id: 0}); err == nil {
+		t.Error("expected dead wizard to be unable to attack")
+	}
+}
+
+func Test_DiscardHand(t *testing.T) {
+	g := testGame([]CardName{Librarian})
+	g.Players[0].Hand = []CardName{Aquarius, Dralio}
+	g = g.playCards(0, DracusPyrio)
+	g, _ = g.target(target{pID: 0, id: 0})
+
+	expected := []CardName{Aquarius, Dralio, DracusPyrio}
+	if d := g.Players[0].Discard; !slices.Equal(d, expected) {
+		t.Errorf("expected %v got %v", expected, d)
+	}
+}
+
+func Test_DragonDiesAttacking(t *testing.T) {
+	g := testGame([]CardName{Librarian, Dragonius}, []CardName{Librarian})
+	g.Field[1][0].attached = Mortius
+	g, pt, _ := g.addPerm(1, Perm{CName: Mortius})
+	p := g.Permanents[pt]
+	p.AttachedTo = target{pID: 1, area: Wizard, id: 0}
+	g.Permanents[pt] = p
+
+	g.Field[1][0].HP = 1
+	g.Dragons[0][0].HP = 1
+	g, err := g.attack(target{pID: 0, id: 0, area: Permanent}, target{pID: 1, id: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.Permanents[PermTarget{0, 0}]; ok {
+		t.Error("expected the dead dragon to stay removed")
+	}
+	if !slices.Contains(g.Players[0].Discard, Dragonius) {
+		t.Errorf("expected Dragonius in the discard pile: %v", g.Players[0].Discard)
+	}
+}
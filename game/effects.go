@@ -0,0 +1,520 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Effect is one step of what a card does. Cards list their effects in
+// cards.json and State.runEffects runs them in order.
+type Effect struct {
+	Op      string   `json:"op"`
+	Amount  int      `json:"amount,omitempty"`
+	Rule    string   `json:"rule,omitempty"`    // GameRules value used instead of Amount, "attack" is the attack's damage
+	Target  string   `json:"target,omitempty"`  // who the effect applies to, see effectTargets
+	If      string   `json:"if,omitempty"`      // skipped unless this condition holds
+	Status  string   `json:"status,omitempty"`  // for "status"
+	Hostile bool     `json:"hostile,omitempty"` // for "await", the target can't be a teammate
+	Msg     string   `json:"msg,omitempty"`     // printed when the effect happens
+	Then    []Effect `json:"then,omitempty"`    // for "await", run on the chosen target
+}
+
+// Wizards use Atk0 and Atk1. Spells and perms run Play when played,
+// perms with an ability run Activate and perms that react to the game
+// name their handler in Passive.
+type CardEffects struct {
+	Atk0     []Effect `json:"atk1,omitempty"`
+	Atk1     []Effect `json:"atk2,omitempty"`
+	Play     []Effect `json:"play,omitempty"`
+	Activate []Effect `json:"activate,omitempty"`
+	Passive  string   `json:"passive,omitempty"` // see permTriggers
+	Needs    string   `json:"needs,omitempty"`   // condition for the card to be playable
+}
+
+// What the running effects are about. atkr is the attacking wizard or
+// the activated perm, defr the attacked card or the chosen target.
+type effectCtx struct {
+	player playerID
+	source CardName
+	atkr   target
+	defr   target
+	perm   PermTarget // perm being played
+	spell  bool       // a card is being played
+	dmg    int        // damage of the attack
+	killed bool       // the attack killed defr
+}
+
+type effectOp func(s State, e Effect, ctx *effectCtx) (State, error)
+
+// Set in init since ops like "repeat" end up running effects themselves
+var effectOps map[string]effectOp
+
+func init() {
+	effectOps = map[string]effectOp{
+		"hit":          hitOp,
+		"pierce":       pierceOp,
+		"handBonus":    handBonusOp,
+		"multiply":     multiplyOp,
+		"damage":       damageOp,
+		"heal":         healOp,
+		"status":       statusOp,
+		"draw":         drawOp,
+		"moreMana":     moreManaOp,
+		"discount":     discountOp,
+		"bounce":       bounceOp,
+		"await":        awaitOp,
+		"strike":       strikeOp,
+		"repeat":       repeatOp,
+		"revive":       reviveOp,
+		"kill":         killOp,
+		"removePerm":   removePermOp,
+		"retrieve":     retrieveOp,
+		"tutor":        tutorOp,
+		"attach":       attachOp,
+		"summonDragon": summonDragonOp,
+		"discardHand":  discardHandOp,
+		"revealDeck":   revealDeckOp,
+	}
+}
+
+var conditions = map[string]func(State, *effectCtx) bool{
+	"killed": func(s State, ctx *effectCtx) bool {
+		return ctx.killed
+	},
+	"alliesDead": func(s State, ctx *effectCtx) bool {
+		return s.allAllies(func(c *Card) bool { return c.HP < 1 }, ctx.atkr)
+	},
+	"cardsInHand": func(s State, ctx *effectCtx) bool {
+		return len(s.Players[ctx.player].Hand) > 0
+	},
+	"cardsInDeck": func(s State, ctx *effectCtx) bool {
+		return len(s.Players[ctx.player].deck) > 0
+	},
+}
+
+func (s State) runEffects(effects []Effect, ctx *effectCtx) (State, error) {
+	for _, e := range effects {
+		if e.If != "" {
+			cond, ok := conditions[e.If]
+			if !ok {
+				return s, ImplmtErr{fmt.Sprintf("Unknown condition %q", e.If)}
+			}
+			if !cond(s, ctx) {
+				continue
+			}
+		}
+		op, ok := effectOps[e.Op]
+		if !ok {
+			return s, ImplmtErr{fmt.Sprintf("Unknown effect %q", e.Op)}
+		}
+		if e.Msg != "" {
+			s.emit(EffectMessage{ctx.player, e.Msg})
+		}
+
+		var err error
+		s, err = op(s, e, ctx)
+		if err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// Effects from the card data, cards that weren't loaded do nothing
+func (s State) cardEffects(name CardName) CardEffects {
+	c, _ := LookupCard(s.cards, name)
+	return c.Effects
+}
+
+// Dragons summoned by Dragonius have no attacks of their own
+var defaultAttack = []Effect{{Op: "hit"}}
+
+// Attack 2 is the extra attack from "strike", it only hits
+func (s State) attackEffects(c *Card, atkNum int) []Effect {
+	var effects []Effect
+	switch atkNum {
+	case 0:
+		effects = s.cardEffects(c.CName).Atk0
+	case 1:
+		effects = s.cardEffects(c.CName).Atk1
+	}
+	if len(effects) == 0 {
+		return defaultAttack
+	}
+	return effects
+}
+
+// The "await" effect whose target is being waited for
+func (s State) awaitedEffect() (Effect, bool) {
+	a := s.awaiting
+	var effects []Effect
+	switch {
+	case !a.isTrue:
+		return Effect{}, false
+	case a.spell:
+		effects = s.cardEffects(a.spellName).Play
+	case a.atkr.area == Permanent:
+		p, ok := s.Permanents[PermTarget{a.atkr.pID, a.atkr.id}]
+		if !ok {
+			return Effect{}, false
+		}
+		effects = s.cardEffects(p.CName).Activate
+	default:
+		c, err := s.cardFromTarget(a.atkr)
+		if err != nil {
+			return Effect{}, false
+		}
+		effects = s.attackEffects(c, a.atkr.atkNum)
+	}
+
+	i := slices.IndexFunc(effects, func(e Effect) bool { return e.Op == "await" })
+	if i < 0 {
+		return Effect{}, false
+	}
+	return effects[i], true
+}
+
+func targetArea(t string) cardType {
+	switch t {
+	case "perm":
+		return Permanent
+	case "deck":
+		return Deck
+	}
+	return Wizard
+}
+
+// Amount, or the rule it names
+func (e Effect) value(s State, ctx *effectCtx) (int, error) {
+	switch e.Rule {
+	case "":
+		return e.Amount, nil
+	case "attack":
+		return ctx.dmg, nil
+	}
+	n, ok := s.Rules.value(e.Rule)
+	if !ok {
+		return 0, ImplmtErr{fmt.Sprintf("Unknown rule %q", e.Rule)}
+	}
+	return n, nil
+}
+
+// Looks up an int rule by its json name
+func (r GameRules) value(name string) (int, bool) {
+	v := reflect.ValueOf(r)
+	for i := range v.NumField() {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if tag == name && v.Field(i).Kind() == reflect.Int {
+			return int(v.Field(i).Int()), true
+		}
+	}
+	return 0, false
+}
+
+// The cards an effect applies to:
+//
+//	target          the attacked card or chosen target (default)
+//	self            the attacker
+//	allies          the attacker's allies
+//	defenderAllies  the other living wizards on the target's field
+//	enemies         every living hostile wizard not on the attacker's field
+//	random          a random living hostile wizard
+func (s State) effectTargets(e Effect, ctx *effectCtx) (State, []*Card, error) {
+	switch e.Target {
+	case "", "target":
+		c, err := s.cardFromTarget(ctx.defr)
+		return s, []*Card{c}, err
+	case "self":
+		c, err := s.cardFromTarget(ctx.atkr)
+		return s, []*Card{c}, err
+	case "allies":
+		res := []*Card{}
+		s.applyToAllies(func(c *Card) {
+			res = append(res, c)
+		}, ctx.atkr)
+		return s, res, nil
+	case "defenderAllies":
+		res := []*Card{}
+		for i := 1; i < s.Rules.MaxFieldLen; i++ {
+			t := target{pID: ctx.defr.pID, id: (ctx.defr.id + i) % s.Rules.MaxFieldLen}
+			c, err := s.cardFromTarget(t)
+			if err == nil && c.Alive() {
+				res = append(res, c)
+			}
+		}
+		return s, res, nil
+	case "enemies":
+		res := []*Card{}
+		for p := range s.NumPlayers {
+			t := target{pID: playerID(p)}
+			if t.pID == ctx.player || s.checkTarget(t) != nil || s.checkHostile(ctx.player, t) != nil {
+				continue
+			}
+			for i := range s.Field[p] {
+				if s.Field[p][i].Alive() {
+					res = append(res, &s.Field[p][i])
+				}
+			}
+		}
+		return s, res, nil
+	case "random":
+		// Picking advances the RNG, so the state is passed back
+		next, t, err := s.randomTarget(ctx.player)
+		if err != nil {
+			return s, nil, err
+		}
+		return next, []*Card{&next.Field[t.pID][t.id]}, nil
+	}
+	return s, nil, ImplmtErr{fmt.Sprintf("Unknown effect target %q", e.Target)}
+}
+
+// Runs f on every target of e
+func (s State) eachTarget(e Effect, ctx *effectCtx, f func(State, *Card, int) State) (State, error) {
+	n, err := e.value(s, ctx)
+	if err != nil {
+		return s, err
+	}
+	next, targets, err := s.effectTargets(e, ctx)
+	if err != nil {
+		return s, err
+	}
+	s = next
+	for _, c := range targets {
+		s = f(s, c, n)
+	}
+	return s, nil
+}
+
+// Ops
+
+// The attack's own damage
+func hitOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	atkrCard, err := s.cardFromTarget(ctx.atkr)
+	if err != nil {
+		return s, err
+	}
+	defrCard, err := s.cardFromTarget(ctx.defr)
+	if err != nil {
+		return s, err
+	}
+
+	defrWasAlive := defrCard.HP > 0
+	s.emit(Attacked{s.locOf(atkrCard), atkrCard.CName, s.locOf(defrCard), defrCard.CName})
+	s = s.dealDmg(atkrCard.CName, defrCard, ctx.dmg)
+
+	ctx.killed = defrWasAlive && !defrCard.Alive()
+	return s.fire(OnAttack, &triggerCtx{
+		player: ctx.atkr.pID,
+		atkr:   atkrCard,
+		card:   defrCard,
+		killed: ctx.killed,
+	}), nil
+}
+
+// Damage that ignores every effect and doesn't count as an attack
+func pierceOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	c, err := s.cardFromTarget(ctx.defr)
+	if err != nil {
+		return s, err
+	}
+	return s.applyDmg(ctx.source, c, ctx.dmg, 0), nil
+}
+
+// One more damage per value cards in hand
+func handBonusOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	n, err := e.value(s, ctx)
+	if err != nil || n < 1 {
+		return s, ImplmtErr{"handBonus needs a positive value"}
+	}
+	ctx.dmg += len(s.Players[ctx.player].Hand) / n
+	return s, nil
+}
+
+func multiplyOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	n, err := e.value(s, ctx)
+	ctx.dmg *= n
+	return s, err
+}
+
+func damageOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.eachTarget(e, ctx, func(s State, c *Card, n int) State {
+		return s.dealDmg(ctx.source, c, n)
+	})
+}
+
+func healOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.eachTarget(e, ctx, func(s State, c *Card, n int) State {
+		return s.dealDmg(ctx.source, c, -n)
+	})
+}
+
+func statusOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	kind := StatusKind(slices.Index(statusNames[:], e.Status))
+	if kind < 0 {
+		return s, ImplmtErr{fmt.Sprintf("Unknown status %q", e.Status)}
+	}
+	return s.eachTarget(e, ctx, func(s State, c *Card, n int) State {
+		return s.addStatus(c, kind)
+	})
+}
+
+func drawOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	n, err := e.value(s, ctx)
+	if err != nil {
+		return s, err
+	}
+	return s.drawCards(ctx.player, n), nil
+}
+
+// Extra mana on the player's next turn
+func moreManaOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	n, err := e.value(s, ctx)
+	s.Players[ctx.player].moreMana += n
+	return s, err
+}
+
+// The next spell costs one less
+func discountOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	s.Players[ctx.player].discountSpell = true
+	return s, nil
+}
+
+// The attacker goes back to the hand, taking value damage first. It
+// keeps its health for when it's played again.
+func bounceOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	recoil, err := e.value(s, ctx)
+	if err != nil {
+		return s, err
+	}
+	c, err := s.cardFromTarget(ctx.atkr)
+	if err != nil {
+		return s, err
+	}
+
+	hp := c.HP - recoil
+	p := &s.Players[ctx.player]
+	p.magicianHealth = hp
+	if hp <= 0 {
+		return s.kill(c), nil
+	}
+	p.Hand = append(p.Hand, c.CName)
+	s.Field[ctx.atkr.pID] = slices.Delete(s.Field[ctx.atkr.pID], ctx.atkr.id, ctx.atkr.id+1)
+	return s, nil
+}
+
+// Waits for the player to pick a target for e.Then, see State.target
+func awaitOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	if !ctx.spell {
+		return s.setAwait(ctx.atkr), nil
+	}
+	s.awaiting = Await{
+		isTrue:    true,
+		spell:     true,
+		spellName: ctx.source,
+		perm:      ctx.perm,
+		caster:    ctx.player,
+	}
+	return s, nil
+}
+
+// Attacks again with the first attack's damage and no effects
+func strikeOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	atkr := ctx.atkr
+	atkr.atkNum = 2
+	return s.strike(atkr, ctx.defr)
+}
+
+// Makes the same attack again, effects included
+func repeatOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.strike(ctx.atkr, ctx.defr)
+}
+
+func reviveOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.eachTarget(e, ctx, func(s State, c *Card, n int) State {
+		return s.revive(c)
+	})
+}
+
+func killOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.eachTarget(e, ctx, func(s State, c *Card, n int) State {
+		return s.kill(c)
+	})
+}
+
+func removePermOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.removePerm(PermTarget{ctx.defr.pID, ctx.defr.id})
+}
+
+// Puts the targeted perm back into the player's hand
+func retrieveOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	pt := PermTarget{ctx.defr.pID, ctx.defr.id}
+	p, ok := s.Permanents[pt]
+	if !ok {
+		return s, TargetPermErr
+	}
+
+	s.emit(PermRetrieved{ctx.player, p.CName})
+	s.Players[ctx.player].Hand = append(s.Players[ctx.player].Hand, p.CName)
+	s, _, err := s.takePerm(pt)
+	return s, err
+}
+
+// Draws the chosen card out of the deck
+func tutorOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	name := CardName(ctx.defr.id)
+	id, ok := s.inDeck(name)
+	if !ok {
+		return s, TargetDeckErr
+	}
+	p := &s.Players[ctx.player]
+	lastId := len(p.deck) - 1
+	p.deck[lastId], p.deck[id] = p.deck[id], p.deck[lastId]
+	s.emit(CardTutored{ctx.player, name})
+	return s.drawCard(ctx.player)
+}
+
+// Attaches the perm being played to the target
+func attachOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	c, err := s.cardFromTarget(ctx.defr)
+	if err != nil {
+		return s, err
+	}
+	p, ok := s.Permanents[ctx.perm]
+	if !ok {
+		return s, TargetPermErr
+	}
+
+	c.attached = ctx.source
+	p.AttachedTo = ctx.defr
+	s.Permanents[ctx.perm] = p
+	s.emit(PermAttached{p.CName, s.locOf(c), c.CName})
+	return s, nil
+}
+
+// The perm being played becomes a dragon that can attack and be attacked
+func summonDragonOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	s.Dragons[ctx.perm.pID][ctx.perm.id] = Card{
+		CName: ctx.source,
+		HP:    s.Rules.DragonHp,
+		Atk0: Attack{
+			Name: "Dragon Breath",
+			Dmg:  s.Rules.DragoniusDmg,
+		},
+	}
+	return s, nil
+}
+
+func discardHandOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	p := &s.Players[ctx.player]
+	hand := p.Hand
+	p.Hand = nil
+	s = s.discard(ctx.player, hand...)
+	s.emit(HandDiscarded{ctx.player, hand})
+	return s, nil
+}
+
+func revealDeckOp(s State, e Effect, ctx *effectCtx) (State, error) {
+	return s.printCardsInDeck(), nil
+}
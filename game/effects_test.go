@@ -0,0 +1,94 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_RandomEffectAdvancesRNG(t *testing.T) {
+	g := seededGame(3)
+	e := Effect{Op: "damage", Target: "random", Amount: 0}
+	ctx := &effectCtx{player: 0, source: Meteorus}
+
+	g1, err := damageOp(g, e, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, _ := damageOp(g1, e, ctx)
+	if g1.rng == g.rng || g2.rng == g1.rng {
+		t.Error("expected each random target to advance the RNG")
+	}
+}
+
+func Test_DataDrivenEffects(t *testing.T) {
+	custom := slices.Clone(cards)
+	pyrus := &custom[PyrusBalio-1]
+	pyrus.Effects = CardEffects{Play: []Effect{
+		{Op: "draw", Amount: 1},
+		{Op: "await", Target: "wizard", Then: []Effect{
+			{Op: "status", Status: "poisoned"},
+		}},
+	}}
+	librarian := &custom[Librarian-1]
+	librarian.Effects.Atk0 = []Effect{
+		{Op: "multiply", Amount: 3, If: "alliesDead"},
+		{Op: "hit"},
+	}
+
+	g := testGame([]CardName{Librarian}, []CardName{Angel})
+	g = g.SetCardData(custom).InitFullDeck()
+	g = g.playCards(0, PyrusBalio)
+	if len(g.Players[0].Hand) != 1 {
+		t.Errorf("expected the custom Pyrus Balio to draw got %v", g.Players[0].Hand)
+	}
+	g, err := g.target(target{pID: 1, id: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Field[1][0].HasStatus(Poisoned) || g.Field[1][0].HP != 8 {
+		t.Error("expected the target to be poisoned and undamaged")
+	}
+
+	g, err = g.attack(target{pID: 0, id: 0}, target{pID: 1, id: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hp := g.Field[1][0].HP; hp != 8-3 {
+		t.Errorf("expected triple damage with no allies got %d hp", hp)
+	}
+
+	pyrus.Effects = CardEffects{Play: []Effect{{Op: "explode"}}}
+	if _, err := g.play(0, CardFromName(custom, PyrusBalio)); err == nil {
+		t.Error("expected an unknown effect to fail")
+	}
+}
+
+func Test_EffectsOnlyFromData(t *testing.T) {
+	custom := slices.Clone(cards)
+	custom[PyrusBalio-1].Effects = CardEffects{}
+	custom[Librarius-1].Effects = CardEffects{}
+
+	g := testGame([]CardName{Librarian})
+	g = g.SetCardData(custom).InitFullDeck().playCards(0, Librarius)
+	if _, err := g.play(0, CardFromName(custom, PyrusBalio)); err == nil {
+		t.Error("expected Pyrus Balio without effects to fail")
+	}
+	g, _ = g.endTurn()
+	g, _ = g.endTurn()
+	if n := len(g.Players[0].Hand); n != 0 {
+		t.Errorf("expected Librarius without a passive to do nothing, drew %d", n)
+	}
+}
+
+func Test_SplashSkipsCorpses(t *testing.T) {
+	g := testGame([]CardName{Pyromancer}, []CardName{Librarian, Angel, Mortician})
+	g = g.DoDmg(1, 1, 8)
+
+	n := len(g.Output.Events(0))
+	g, _ = g.attack(target{pID: 0, id: 0, atkNum: 0}, target{pID: 1, id: 0})
+	for _, e := range g.Output.Events(0)[n:] {
+		if d, ok := e.(DamageDealt); ok && d.Card == Angel {
+			t.Error("splash hit a dead wizard")
+		}
+	}
+}
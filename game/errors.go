@@ -11,6 +11,10 @@ var TargetWizardErr = TargetErr{"Target Wizard doesn't exist"}
 var TargetPermErr = TargetErr{"Perm Not Found"}
 var TargetDeckErr = TargetErr{"Card Not Found Deck"}
 var TargetDragonErr = ImplmtErr{"Target Dragon doesn't exist"}
+var GameOverErr = GameErr{"The game is over"}
+var HandFullErr = GameErr{"Hand is full"}
+var DiscardFirstErr = GameErr{"Choose cards to discard first"}
+var MulliganFirstErr = GameErr{"Mulligan or keep your hand first"}
 
 type ImplmtErr struct {
 	msg string
@@ -67,6 +71,10 @@ func (g State) checkTarget(t target) error {
 	if err := g.checkPlayerID(t.pID); err != nil {
 		return TargetPlayerErr
 	}
+	// Eliminated players' cards are out of the game
+	if g.eliminated[t.pID] {
+		return TargetPlayerErr
+	}
 
 	switch t.area {
 	case Wizard:
@@ -0,0 +1,395 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Event is a structured record of something that happened in the game.
+// Its text is rendered when it is emitted and shown by Output.Lines;
+// silent events are only visible through Output.Events.
+type Event interface {
+	text(s State) string
+}
+
+// Events clients track themselves, kept out of the log
+type silentEvent interface {
+	Event
+	silent()
+}
+
+type TurnStarted struct {
+	Player playerID
+}
+
+func (e TurnStarted) text(s State) string {
+	return fmt.Sprintf("%s's turn", s.Players[e.Player])
+}
+
+type CardDrawn struct {
+	Player    playerID
+	Count     int
+	Requested int
+}
+
+func (e CardDrawn) text(s State) string {
+	p := s.Players[e.Player]
+	switch {
+	case e.Count == 0:
+		return fmt.Sprintf("%s couldn't draw from their empty deck", p)
+	case e.Count < e.Requested && len(p.deck) > 0:
+		return fmt.Sprintf("%s drew %d card(s), their hand is full", p, e.Count)
+	case e.Count < e.Requested:
+		return fmt.Sprintf("%s drew %d card(s), their deck is now empty", p, e.Count)
+	}
+	return fmt.Sprintf("%s drew %d card(s)", p, e.Count)
+}
+
+// Sent privately to the player who drew
+type CardsSeen struct {
+	Player playerID
+	Cards  []CardName
+}
+
+func (e CardsSeen) text(s State) string {
+	names := make([]string, len(e.Cards))
+	for i, c := range e.Cards {
+		names[i] = c.String()
+	}
+	return "You drew " + strings.Join(names, ", ")
+}
+
+type CardPlayed struct {
+	Player playerID
+	Card   CardName
+}
+
+func (e CardPlayed) text(s State) string {
+	return fmt.Sprintf("%s played %s", s.Players[e.Player], e.Card)
+}
+
+// Amount is the damage left after Prevented was blocked by effects
+type DamageDealt struct {
+	Source    CardName
+	Target    Loc
+	Card      CardName
+	Amount    int
+	Prevented int
+}
+
+func (e DamageDealt) text(s State) string {
+	msg := fmt.Sprintf("%s's %s took %d damage",
+		s.Players[e.Target.PID], e.Card, e.Amount)
+	if e.Prevented > 0 {
+		msg += fmt.Sprintf(", %d was prevented", e.Prevented)
+	}
+	return msg
+}
+
+type Healed struct {
+	Target Loc
+	Card   CardName
+	Amount int
+}
+
+func (e Healed) text(s State) string {
+	return fmt.Sprintf("%s's %s healed %d HP", s.Players[e.Target.PID], e.Card, e.Amount)
+}
+
+type WizardDied struct {
+	Target Loc
+	Card   CardName
+}
+
+func (e WizardDied) text(s State) string {
+	return fmt.Sprintf("%s's %s died", s.Players[e.Target.PID], e.Card)
+}
+
+type PermAttached struct {
+	Perm   CardName
+	Target Loc
+	Card   CardName
+}
+
+func (e PermAttached) text(s State) string {
+	return fmt.Sprintf("Attached %s to %s", e.Perm, e.Card)
+}
+
+type PermRemoved struct {
+	Owner playerID
+	Perm  CardName
+}
+
+func (e PermRemoved) text(s State) string {
+	return fmt.Sprintf("Removed %s", e.Perm)
+}
+
+// Status is the card's status after it was applied
+type StatusApplied struct {
+	Target Loc
+	Card   CardName
+	Status Status
+}
+
+func (e StatusApplied) text(s State) string {
+	return fmt.Sprintf("%s's %s is %s", s.Players[e.Target.PID], e.Card, e.Status.Kind)
+}
+
+type StatusExpired struct {
+	Target Loc
+	Card   CardName
+	Kind   StatusKind
+}
+
+func (e StatusExpired) text(s State) string {
+	return fmt.Sprintf("%s's %s is no longer %s", s.Players[e.Target.PID], e.Card, e.Kind)
+}
+
+type ManaChanged struct {
+	Player playerID
+	Mana   int
+	Delta  int
+}
+
+func (e ManaChanged) text(s State) string {
+	return fmt.Sprintf("%s has %d mana", s.Players[e.Player], e.Mana)
+}
+
+func (e ManaChanged) silent() {}
+
+type PlayerEliminated struct {
+	Player playerID
+}
+
+func (e PlayerEliminated) text(s State) string {
+	return fmt.Sprintf("%s has been eliminated", s.Players[e.Player])
+}
+
+type GameEnded struct {
+	Winner playerID
+	Team   int
+	Draw   bool
+}
+
+func (e GameEnded) text(s State) string {
+	if e.Draw {
+		return "The game ended in a draw"
+	}
+	names := []string{s.Players[e.Winner].String()}
+	for _, p := range s.Teammates(e.Winner) {
+		names = append(names, s.Players[p].String())
+	}
+	return fmt.Sprintf("%s won the game", strings.Join(names, " and "))
+}
+
+func (s *State) emit(e Event) {
+	s.Output.Messages = append(s.Output.Messages, Message{
+		Text:  "-" + e.text(*s),
+		Event: e,
+	})
+}
+
+func (s *State) emitPrivate(p playerID, e Event) {
+	s.Output.Messages = append(s.Output.Messages, Message{
+		Text:     e.text(*s),
+		Event:    e,
+		Private:  true,
+		Receiver: p,
+	})
+}
+
+func (s *State) setManaTo(n int) {
+	if n == s.Mana {
+		return
+	}
+	s.emit(ManaChanged{s.CurrentPlayer, n, n - s.Mana})
+	s.Mana = n
+}
+
+// Finds where a card pointer returned by cardFromTarget lives
+func (s State) locOf(c *Card) Loc {
+	for p := range s.NumPlayers {
+		for i := range s.Field[p] {
+			if &s.Field[p][i] == c {
+				return Loc{Wizard, p, i}
+			}
+		}
+		for i := range s.Dragons[p] {
+			if &s.Dragons[p][i] == c {
+				return Loc{Permanent, p, i}
+			}
+		}
+	}
+	return Loc{}
+}
+
+// Text from a card's effects, like an await's prompt
+type EffectMessage struct {
+	Player playerID
+	Msg    string
+}
+
+func (e EffectMessage) text(s State) string {
+	return e.Msg
+}
+
+type Attacked struct {
+	Attacker Loc
+	Card     CardName
+	Defender Loc
+	Target   CardName
+}
+
+func (e Attacked) text(s State) string {
+	return fmt.Sprintf("%s's %s attacked %s's %s",
+		s.Players[e.Attacker.PID], e.Card, s.Players[e.Defender.PID], e.Target)
+}
+
+// Mortius hitting back at the wizard that killed its host
+type Avenged struct {
+	Card     CardName
+	Attacker Loc
+	Target   CardName
+}
+
+func (e Avenged) text(s State) string {
+	return fmt.Sprintf("%s's Mortius attacked %s", e.Card, e.Target)
+}
+
+// A perm's passive that has no other event, like Librarius' draw
+type PassiveUsed struct {
+	Owner playerID
+	Perm  CardName
+}
+
+func (e PassiveUsed) text(s State) string {
+	return fmt.Sprintf("%s activated", e.Perm)
+}
+
+// Mana saved for the player's next turn
+type ManaGiven struct {
+	Player playerID
+	Source CardName
+	Amount int
+}
+
+func (e ManaGiven) text(s State) string {
+	return fmt.Sprintf("%s gave %d mana to %s", e.Source, e.Amount, s.Players[e.Player].Name)
+}
+
+type PermRetrieved struct {
+	Player playerID
+	Perm   CardName
+}
+
+func (e PermRetrieved) text(s State) string {
+	return fmt.Sprintf("Retrieving %s", e.Perm)
+}
+
+// A card searched out of the deck
+type CardTutored struct {
+	Player playerID
+	Card   CardName
+}
+
+func (e CardTutored) text(s State) string {
+	return fmt.Sprintf("%s put %s back into their hand", s.Players[e.Player].Name, e.Card)
+}
+
+// Burned is set when the card came off the deck because the hand was full
+type CardDiscarded struct {
+	Player playerID
+	Card   CardName
+	Burned bool
+}
+
+func (e CardDiscarded) text(s State) string {
+	if e.Burned {
+		return fmt.Sprintf("%s's hand is full, %s was discarded", s.Players[e.Player], e.Card)
+	}
+	return fmt.Sprintf("%s discarded %s", s.Players[e.Player], e.Card)
+}
+
+type HandDiscarded struct {
+	Player playerID
+	Cards  []CardName
+}
+
+func (e HandDiscarded) text(s State) string {
+	return fmt.Sprintf("%s removed all cards from their hand", s.Players[e.Player])
+}
+
+// The player's hand is over Rules.MaxHandSize at the end of their turn
+type DiscardRequired struct {
+	Player playerID
+	Count  int
+}
+
+func (e DiscardRequired) text(s State) string {
+	return fmt.Sprintf("%s has to discard %d card(s)", s.Players[e.Player], e.Count)
+}
+
+// Sent when a player starts a turn with Rules.DeckOutLoses and no cards
+type DeckEmpty struct {
+	Player playerID
+}
+
+func (e DeckEmpty) text(s State) string {
+	return fmt.Sprintf("%s has no cards left to draw", s.Players[e.Player])
+}
+
+type MulliganOffered struct {
+	Player playerID
+}
+
+func (e MulliganOffered) text(s State) string {
+	return fmt.Sprintf("%s may mulligan", s.Players[e.Player])
+}
+
+// Count is 0 when the player kept their hand
+type Mulliganed struct {
+	Player playerID
+	Count  int
+}
+
+func (e Mulliganed) text(s State) string {
+	if e.Count == 0 {
+		return fmt.Sprintf("%s kept their hand", s.Players[e.Player])
+	}
+	return fmt.Sprintf("%s mulliganed %d card(s)", s.Players[e.Player], e.Count)
+}
+
+// Spell is None when an attack's follow-up was skipped
+type Cancelled struct {
+	Player playerID
+	Spell  CardName
+}
+
+func (e Cancelled) text(s State) string {
+	if e.Spell == None {
+		return fmt.Sprintf("%s skipped the attack", s.Players[e.Player])
+	}
+	return fmt.Sprintf("%s cancelled %s", s.Players[e.Player], e.Spell)
+}
+
+// Sent privately, Unique lists each card once for choosing a target
+type DeckShown struct {
+	Player playerID
+	Cards  []CardName
+	Unique bool
+}
+
+func (e DeckShown) text(s State) string {
+	if len(e.Cards) == 0 {
+		return "Your deck is empty"
+	}
+	lines := make([]string, len(e.Cards))
+	for i, c := range e.Cards {
+		if e.Unique {
+			lines[i] = fmt.Sprintf("Id: %d - %s", c, c)
+		} else {
+			lines[i] = fmt.Sprintf("%d: %s", i, c)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
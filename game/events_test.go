@@ -0,0 +1,123 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_Events(t *testing.T) {
+	g := testGame([]CardName{Librarian, Shieldmancer}, []CardName{Angel}).InitFullDeck()
+
+	g = g.doAtk(0)
+	g, _ = g.attack(target{pID: 0, id: 1, atkNum: 0}, target{pID: 1, id: 0})
+	g = g.DoDmg(0, 0, 8)
+	g = g.DoDmg(1, 0, 8)
+
+	var drawn *CardDrawn
+	var dmg []DamageDealt
+	var died []WizardDied
+	for _, e := range g.Output.Events(0) {
+		switch e := e.(type) {
+		case CardDrawn:
+			drawn = &e
+		case DamageDealt:
+			dmg = append(dmg, e)
+		case WizardDied:
+			died = append(died, e)
+		}
+	}
+
+	if drawn == nil || drawn.Player != 0 || drawn.Count != 1 {
+		t.Errorf("expected player 0 to draw 1 card, got %v", drawn)
+	}
+	if len(dmg) != 4 {
+		t.Fatalf("expected 4 damage events got %d", len(dmg))
+	}
+	expected := DamageDealt{Shieldmancer, Loc{Wizard, 1, 0}, Angel, 1, 0}
+	if dmg[1] != expected {
+		t.Errorf("expected %v got %v", expected, dmg[1])
+	}
+	if dmg[2].Prevented != 8 || dmg[2].Amount != 0 {
+		t.Errorf("expected protected wizard to prevent all damage, got %v", dmg[2])
+	}
+	if len(died) != 1 || died[0].Card != Angel {
+		t.Errorf("expected Angel to die got %v", died)
+	}
+}
+
+// Every line of the log comes from an event, so clients can rebuild it
+func Test_LogFromEvents(t *testing.T) {
+	for seed := range uint64(10) {
+		g := seededGame(seed)
+		g.Players[0].Hand = append(g.Players[0].Hand, Librarius, Conjorius, Mortius)
+		r := rand.New(rand.NewSource(int64(seed)))
+		for step := 0; step < 200 && !g.GameOver(); step++ {
+			actions := g.LegalActions(g.CurrentPlayer)
+			g, _ = g.Apply(actions[r.Intn(len(actions))])
+		}
+		for _, msg := range g.Output.Messages {
+			if msg.Event == nil {
+				t.Errorf("seed %d: %q has no event", seed, msg.Text)
+			}
+		}
+
+		data, err := g.Save()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(data, cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded.Output.Events(0), g.Output.Events(0)) {
+			t.Errorf("seed %d: expected events to survive a save", seed)
+		}
+	}
+}
+
+func Test_SilentEvents(t *testing.T) {
+	g := seededGame(1)
+	g, _ = g.Apply(EndTurn{})
+	lines := g.Output.Lines(1)
+	if !slices.Contains(lines, "-Bob's turn") {
+		t.Errorf("expected the turn to name the player, got %v", lines)
+	}
+	for _, l := range lines {
+		if strings.Contains(l, "mana") {
+			t.Errorf("expected mana changes to stay out of the log, got %q", l)
+		}
+	}
+	if !slices.ContainsFunc(g.Output.Events(1), func(e Event) bool {
+		_, ok := e.(ManaChanged)
+		return ok
+	}) {
+		t.Error("expected mana changes in the events")
+	}
+}
+
+func Test_PrivateEvents(t *testing.T) {
+	g := testGame().InitFullDeck()
+	g = g.drawCards(0, 2)
+
+	seen := func(p playerID) bool {
+		for _, e := range g.Output.Events(p) {
+			if _, ok := e.(CardsSeen); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !seen(0) {
+		t.Error("expected drawer to see their cards")
+	}
+	if seen(1) {
+		t.Error("other players shouldn't see drawn cards")
+	}
+	if lines := g.Output.Lines(1); len(lines) != 2 {
+		t.Errorf("expected 2 public lines got %v", lines)
+	}
+}
@@ -1,19 +1,12 @@
 package game
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 )
 
-func isValidCardNumber(n int) error {
-	if n < 1 || n > 30 {
-		return errors.New("Invalid card number")
-	}
-	return nil
-}
-
 func convertArgs(n int, args ...string) ([]int, error) {
 	lenArgs := len(args)
 	if lenArgs < n {
@@ -34,171 +27,112 @@ func convertArgs(n int, args ...string) ([]int, error) {
 }
 
 func (g State) Execute(cards []Cdata, args ...string) (State, error) {
-	switch args[0] {
-	case "target":
-		nums, err := convertArgs(2, args[1:]...)
-		if err != nil {
-			return g, err
-		}
-
-		g, err = g.target(target{pID: playerID(nums[0]), id: nums[1]})
-		if err != nil {
-			return g, err
-		}
-
-		return g, nil
-	case "targetperm":
-		nums, err := convertArgs(2, args[1:]...)
-		if err != nil {
-			return g, err
-		}
+	a, err := ParseAction(args...)
+	if err != nil {
+		return g, err
+	}
 
-		g, err = g.target(target{area: Permanent, 
-					pID: playerID(nums[0]), id: nums[1]})
-		if err != nil {
-			return g, err
-		}
+	return g.SetCardData(cards).Apply(a)
+}
 
-		return g, nil
-	case "create":
-		nums, err := convertArgs(1, args[1:]...)
-		if err != nil {
-			return g, err
-		}
+// args in the form Action.Args gives them, with cards by ID since card
+// numbers depend on the order cards were loaded in
+func canonicalArgs(args []string) []string {
+	a, err := ParseAction(args...)
+	if err != nil {
+		return slices.Clone(args)
+	}
+	return a.Args()
+}
 
-		if err := isValidCardNumber(nums[0]); err != nil {
-			return g, err
-		}
+// Parses a command in the format used by State.Execute
+func ParseAction(args ...string) (Action, error) {
+	if len(args) == 0 {
+		return nil, InputErr{"Empty command"}
+	}
 
-		game, err := g.play(g.CurrentPlayer, CardFromName(cards, CardName(nums[0])))
-		if err != nil {
-			return g, err
+	// Cards are given by ID or number, see ParseCardName
+	switch args[0] {
+	case "targetdeck", "create":
+		if len(args) < 2 {
+			return nil, InputErr{fmt.Sprintf("Expected 1 args, got %d", len(args)-1)}
 		}
-
-		return game, nil
-	case "setmana":
-		nums, err := convertArgs(1, args[1:]...)
+		card, err := ParseCardName(args[1])
 		if err != nil {
-			return g, err
+			return nil, err
 		}
-
-		return g.setMana(nums[0]), nil
-	case "attack":
-		nums, err := convertArgs(5, args[1:]...)
-		if err != nil {
-			return g, err
+		if args[0] == "create" {
+			return Create{card}, nil
 		}
+		return TargetDeck{card}, nil
+	}
 
-		newG, err := g.attack(
-			target{
-				pID:    playerID(nums[0]),
-				id:     nums[1],
-				atkNum: nums[2],
-			},
-			target{
-				pID: playerID(nums[3]),
-				id:  nums[4],
-			})
+	expected := map[string]int{
+		"target":     2,
+		"targetperm": 2,
+		"setmana":    1,
+		"attack":     5,
+		"atk":        7,
+		"play":       1,
+		"discard":    1,
+		"activate":   2,
+	}
 
-		if err != nil {
-			return g, err
-		}
+	nums, err := convertArgs(expected[args[0]], args[1:]...)
+	if err != nil {
+		return nil, err
+	}
 
-		return newG, nil
+	switch args[0] {
+	case "target":
+		return Target{nums[0], nums[1]}, nil
+	case "targetperm":
+		return TargetPerm{nums[0], nums[1]}, nil
+	case "setmana":
+		return SetMana{nums[0]}, nil
+	case "attack":
+		return DeclareAttack{
+			Attacker: Loc{PID: nums[0], ID: nums[1]},
+			AtkNum:   nums[2],
+			Defender: Loc{PID: nums[3], ID: nums[4]},
+		}, nil
 	case "atk":
-		nums, err := convertArgs(7, args[1:]...)
-		if err != nil {
-			return g, err
-		}
-
-		newG, err := g.attack(
-			target{
-				area:   cardType(nums[0]),
-				pID:    playerID(nums[1]),
-				id:     nums[2],
-				atkNum: nums[3],
-			},
-			target{
-				area:   cardType(nums[4]),
-				pID: playerID(nums[5]),
-				id:  nums[6],
-			})
-
-		if err != nil {
-			return g, err
-		}
-
-		return newG, nil
+		return DeclareAttack{
+			Attacker: Loc{Area: cardType(nums[0]), PID: nums[1], ID: nums[2]},
+			AtkNum:   nums[3],
+			Defender: Loc{Area: cardType(nums[4]), PID: nums[5], ID: nums[6]},
+		}, nil
 	case "play":
-		nums, err := convertArgs(1, args[1:]...)
-		if err != nil {
-			return g, err
-		}
-
-		idx := nums[0]
-		h := g.Players[g.CurrentPlayer].Hand
-		if idx < 0 || idx > len(h)-1 {
-			return g, err
-		}
-
-		card := CardFromName(cards, h[idx])
-		game, err := g.play(g.CurrentPlayer, card)
-		if err != nil {
-			return g, err
-		}
-
-		game, err = game.removeFromHand(game.CurrentPlayer, idx)
-		if err != nil {
-			return g, err
-		}
-
-		return game, nil
+		return PlayFromHand{nums[0]}, nil
+	case "discard":
+		return DiscardFromHand{nums[0]}, nil
 	case "draw":
-		g, err := g.drawCard(g.CurrentPlayer)
-		if err != nil {
-			return g, err
-		}
-		return g, nil
+		return Draw{}, nil
 	case "showdeck":
-		return g.showDeck(g.CurrentPlayer), nil
+		return ShowDeck{}, nil
 	case "activate":
-		//todo
-		nums, err := convertArgs(2, args[1:]...)
-		if err != nil {
-			return g, err
-		}
-
-		g, err = g.activatePerm(PermTarget{playerID(nums[0]), nums[1]})
-		return g, err
+		return Activate{nums[0], nums[1]}, nil
 	case "end":
-		g, err := g.endTurn()
-		if err != nil {
-			return g, err
-		}
-		return g, nil
+		return EndTurn{}, nil
+	case "cancel":
+		return Cancel{}, nil
+	case "mulligan":
+		if len(nums) == 0 {
+			return Mulligan{}, nil
+		}
+		return Mulligan{nums}, nil
+	case "keep":
+		return Keep{}, nil
 	default:
-		return g, errors.New("Invalid Command")
+		return nil, errors.New("Invalid Command")
 	}
 }
 
-func GetCardData(data []byte) []Cdata {
-	var rawCards []json.RawMessage
-	err := json.Unmarshal(data, &rawCards)
-	if err != nil {
-		panic("Couldn't parse card data")
+// Validates and loads card files, problems are returned as CardDataErrs
+func GetCardData(data ...[]byte) ([]Cdata, error) {
+	files := make([]cardFile, len(data))
+	for i, d := range data {
+		files[i] = cardFile{data: d}
 	}
-
-	cards := make([]Cdata, 30)
-	for i, v := range rawCards {
-		var c Cdata
-		err := json.Unmarshal(v, &c)
-		if err != nil {
-			panic("Couldn't parse card data")
-		}
-
-		c.CName = CardName(i + 1)
-		cards[i] = c
-	}
-
-	return cards
+	return loadCardFiles(files)
 }
@@ -0,0 +1,33 @@
+package game
+
+// How many cards the current player has to discard to end their turn
+func (s State) cardsOverLimit() int {
+	return max(0, len(s.Players[s.CurrentPlayer].Hand)-s.Rules.MaxHandSize)
+}
+
+// The current player has to discard before their turn can end
+func (s State) Discarding() bool {
+	return s.awaiting.discarding
+}
+
+func (s State) discardFromHand(idx int) (State, error) {
+	if !s.awaiting.discarding {
+		return s, GameErr{"Nothing to discard"}
+	}
+	p := s.CurrentPlayer
+	hand := s.Players[p].Hand
+	if idx < 0 || idx >= len(hand) {
+		return s, InputErr{"Hand index out of bounds"}
+	}
+
+	c := hand[idx]
+	s, _ = s.removeFromHand(p, idx)
+	s = s.discard(p, c)
+	s.emit(CardDiscarded{p, c, false})
+
+	if s.cardsOverLimit() > 0 {
+		return s, nil
+	}
+	s = s.cancelAwait()
+	return s.endTurn()
+}
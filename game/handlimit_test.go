@@ -0,0 +1,82 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_HandLimit(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxHandSize = 2
+	g := phaseGame(rules, Librarian, Angel, Pyromancer)
+	g.Players[0].Hand = []CardName{Protectio, Enhancius, Aquarius, Dralio}
+
+	g, err := g.Apply(EndTurn{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Discarding() || g.CurrentPlayer != 0 {
+		t.Fatal("expected the turn to wait for discards")
+	}
+	if _, err := g.Apply(EndTurn{}); err != DiscardFirstErr {
+		t.Errorf("expected DiscardFirstErr got %v", err)
+	}
+	if _, err := g.Apply(Cancel{}); err == nil {
+		t.Error("expected discarding to not be cancellable")
+	}
+	if n := len(g.LegalActions(0)); n != 4 {
+		t.Errorf("expected 4 legal discards got %d", n)
+	}
+
+	g, err = g.Apply(DiscardFromHand{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.CurrentPlayer != 0 {
+		t.Fatal("expected another discard")
+	}
+	g, err = g.Execute(cards, "discard", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.CurrentPlayer != 1 || g.Discarding() {
+		t.Error("expected the turn to end after discarding down to the limit")
+	}
+	if len(g.Players[0].Hand) != 2 || len(g.Players[0].Discard) != 2 {
+		t.Errorf("expected 2 cards in hand and 2 discarded got %v %v",
+			g.Players[0].Hand, g.Players[0].Discard)
+	}
+}
+
+func Test_Overdraw(t *testing.T) {
+	for _, rule := range []OverdrawRule{OverdrawKeep, OverdrawBurn, OverdrawSkip} {
+		g, _ := newTestGame(2)
+		g.Rules.MaxHandSize = 1
+		g.Rules.Overdraw = rule
+		g.Players[0].Hand = []CardName{Protectio}
+		g.Players[0].deck = []CardName{Librarian, Angel}
+
+		g = g.drawCards(0, 1)
+		p := g.Players[0]
+		switch rule {
+		case OverdrawKeep:
+			if len(p.Hand) != 2 {
+				t.Errorf("keep: expected the card to be drawn got %v", p.Hand)
+			}
+		case OverdrawBurn:
+			if len(p.Hand) != 1 || len(p.deck) != 1 || !slices.Equal(p.Discard, []CardName{Angel}) {
+				t.Errorf("burn: expected Angel discarded got %v %v", p.Hand, p.Discard)
+			}
+		case OverdrawSkip:
+			if len(p.Hand) != 1 || len(p.deck) != 2 {
+				t.Errorf("skip: expected the card to stay in the deck got %v", p.Hand)
+			}
+		}
+	}
+
+	rules := DefaultRules()
+	rules.Overdraw = "never"
+	if rules.Validate() == nil {
+		t.Error("expected an unknown overdraw rule to be invalid")
+	}
+}
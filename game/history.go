@@ -0,0 +1,116 @@
+package game
+
+import "errors"
+
+var (
+	NothingToUndoErr = errors.New("Nothing to undo")
+	NothingToRedoErr = errors.New("Nothing to redo")
+	HiddenInfoErr    = errors.New("Can't undo past revealed cards")
+)
+
+type step struct {
+	before, after State
+	args          []string
+	hidden        bool
+}
+
+// Undo/redo for local games. Record every applied command with the
+// states before and after it.
+type History struct {
+	// Max number of steps that can be undone, 0 is unlimited
+	Limit int
+	// Don't undo steps that revealed hidden information, like a draw
+	BlockHidden bool
+
+	undo, redo []step
+}
+
+// Whether going from s to next showed something a player shouldn't
+// be able to take back: a deck changed, the rng was used or a player
+// was shown cards privately.
+func (s State) revealsHidden(next State) bool {
+	if s.rng != next.rng {
+		return true
+	}
+	for p := range s.NumPlayers {
+		if len(s.Players[p].deck) != len(next.Players[p].deck) {
+			return true
+		}
+	}
+	for _, msg := range next.Output.Messages[len(s.Output.Messages):] {
+		if msg.Private {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *History) Record(before, after State, args ...string) {
+	h.undo = append(h.undo, step{
+		before: before,
+		after:  after,
+		args:   canonicalArgs(args),
+		hidden: before.revealsHidden(after),
+	})
+	if h.Limit > 0 && len(h.undo) > h.Limit {
+		h.undo = h.undo[len(h.undo)-h.Limit:]
+	}
+	h.redo = nil
+}
+
+func (h History) CanUndo() bool {
+	if len(h.undo) == 0 {
+		return false
+	}
+	return !h.BlockHidden || !h.undo[len(h.undo)-1].hidden
+}
+
+func (h History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+func (h *History) Undo() (State, error) {
+	if len(h.undo) == 0 {
+		return State{}, NothingToUndoErr
+	}
+	if !h.CanUndo() {
+		return State{}, HiddenInfoErr
+	}
+	last := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, last)
+	return last.before, nil
+}
+
+// Undoes every step of the turn the last step was taken in. Returns
+// the number of steps undone.
+func (h *History) UndoTurn() (State, int, error) {
+	if len(h.undo) == 0 {
+		return State{}, 0, NothingToUndoErr
+	}
+	player := h.undo[len(h.undo)-1].before.CurrentPlayer
+	var s State
+	n := 0
+	for len(h.undo) > 0 && h.undo[len(h.undo)-1].before.CurrentPlayer == player {
+		if !h.CanUndo() {
+			break
+		}
+		s, _ = h.Undo()
+		n++
+	}
+	if n == 0 {
+		return State{}, 0, HiddenInfoErr
+	}
+	return s, n, nil
+}
+
+// Returns the state after the redone step and the command that made it
+func (h *History) Redo() (State, []string, error) {
+	if len(h.redo) == 0 {
+		return State{}, nil, NothingToRedoErr
+	}
+	next := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, next)
+	return next.after, next.args, nil
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_History(t *testing.T) {
+	g := seededGame(5)
+	h := History{BlockHidden: true}
+
+	apply := func(args ...string) {
+		next, err := g.Execute(cards, args...)
+		if err != nil {
+			t.Fatalf("%v: %s", args, err)
+		}
+		h.Record(g, next, args...)
+		g = next
+	}
+
+	start := g
+	apply("attack", "0", "2", "0", "1", "0")
+	afterAtk := g
+	apply("attack", "0", "1", "0", "0", "2")
+
+	undone, err := h.Undo()
+	if err != nil || !reflect.DeepEqual(undone, afterAtk) {
+		t.Fatalf("undo didn't restore previous state: %v", err)
+	}
+	redone, args, err := h.Redo()
+	if err != nil || !reflect.DeepEqual(redone, g) || args[0] != "atk" {
+		t.Fatalf("redo didn't restore state: %v", err)
+	}
+	if _, _, err := h.Redo(); err != NothingToRedoErr {
+		t.Errorf("expected nothing to redo got %v", err)
+	}
+
+	s, n, err := h.UndoTurn()
+	if err != nil || n != 2 || !reflect.DeepEqual(s, start) {
+		t.Fatalf("undo turn undid %d steps: %v", n, err)
+	}
+	h.Redo()
+	h.Redo()
+
+	apply("end")
+	if _, err := h.Undo(); err != HiddenInfoErr {
+		t.Errorf("expected the draw to block undo got %v", err)
+	}
+	h.BlockHidden = false
+	if _, err := h.Undo(); err != nil {
+		t.Error(err)
+	}
+
+	h = History{}
+	h.Record(g, g, "create", fmt.Sprint(int(Angel)))
+	h.Undo()
+	if _, args, _ := h.Redo(); !slices.Equal(args, []string{"create", "angel"}) {
+		t.Errorf("expected cards to be recorded by ID, got %v", args)
+	}
+
+	h = History{Limit: 1}
+	apply("attack", "1", "2", "0", "0", "0")
+	apply("attack", "1", "1", "0", "1", "2")
+	h.Undo()
+	if _, err := h.Undo(); err != NothingToUndoErr {
+		t.Errorf("expected limit to stop undo got %v", err)
+	}
+}
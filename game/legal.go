@@ -0,0 +1,228 @@
+package game
+
+import "slices"
+
+// Lists every action the player can take right now. Debug actions such as
+// Create and SetMana are never included.
+func (s State) LegalActions(p playerID) []Action {
+	if s.gameOver || p != s.CurrentPlayer {
+		return nil
+	}
+
+	if s.phase == MulliganPhase {
+		return s.legalMulligans(p)
+	}
+	if s.awaiting.discarding {
+		return s.legalDiscards(p)
+	}
+	if s.awaiting.isTrue {
+		actions := s.legalTargets()
+		if s.cancellable() {
+			actions = append(actions, Cancel{})
+		}
+		// Ending the turn drops the target, only offered when stuck
+		if len(actions) == 0 {
+			actions = append(actions, EndTurn{})
+		}
+		return actions
+	}
+
+	actions := slices.Concat(
+		s.legalPlays(p),
+		s.legalAttacks(p),
+		s.legalActivations(p),
+	)
+	return append(actions, EndTurn{})
+}
+
+func (s State) legalDiscards(p playerID) (res []Action) {
+	for i := range s.Players[p].Hand {
+		res = append(res, DiscardFromHand{i})
+	}
+	return
+}
+
+// Every non empty set of cards for partial mulligans
+func (s State) legalMulligans(p playerID) []Action {
+	res := []Action{Keep{}}
+	n := len(s.Players[p].Hand)
+	if s.Rules.Mulligan != MulliganPartial {
+		return append(res, Mulligan{})
+	}
+	for set := 1; set < 1<<n; set++ {
+		idx := []int{}
+		for i := range n {
+			if set&(1<<i) != 0 {
+				idx = append(idx, i)
+			}
+		}
+		res = append(res, Mulligan{idx})
+	}
+	return res
+}
+
+func (s State) sortedPermTargets() (res []PermTarget) {
+	for _, perms := range s.SortedPerms() {
+		for _, pt := range perms {
+			res = append(res, PermTarget{playerID(pt.PID), pt.ID})
+		}
+	}
+	return
+}
+
+func (s State) legalPlays(p playerID) (res []Action) {
+	if s.cards == nil || s.canPlay() != nil {
+		return
+	}
+
+	for i, name := range s.Players[p].Hand {
+		card, err := s.cardData(name)
+		if err != nil || s.checkPlayable(p, card) != nil {
+			continue
+		}
+		res = append(res, PlayFromHand{i})
+	}
+	return
+}
+
+// Living wizards and dragons that can be attacked
+func (s State) defenders() (res []Loc) {
+	for p := range s.NumPlayers {
+		if s.eliminated[p] {
+			continue
+		}
+		for i, c := range s.Field[p] {
+			if c.Alive() {
+				res = append(res, Loc{Wizard, p, i})
+			}
+		}
+	}
+	for _, pt := range s.sortedPermTargets() {
+		t := target{area: Permanent, pID: pt.pID, id: pt.id}
+		if _, err := s.cardFromTarget(t); err == nil {
+			res = append(res, Loc{Permanent, int(pt.pID), pt.id})
+		}
+	}
+	return
+}
+
+func (s State) legalAttacks(p playerID) (res []Action) {
+	defenders := s.defenders()
+
+	for i, c := range s.Field[p] {
+		if c.HP == 0 || c.HasStatus(Stunned) {
+			continue
+		}
+		if s.canAttack(target{pID: p, id: i}) != nil {
+			continue
+		}
+		atks := 2
+		if c.HasStatus(Silenced) {
+			atks = 1
+		}
+		for atkNum := range atks {
+			for _, d := range defenders {
+				if s.checkHostile(p, d.target()) != nil {
+					continue
+				}
+				res = append(res, DeclareAttack{Loc{Wizard, int(p), i}, atkNum, d})
+			}
+		}
+	}
+
+	for _, pt := range s.sortedPermTargets() {
+		perm := s.Permanents[pt]
+		if pt.pID != p || perm.CName != Dragonius || perm.Activated {
+			continue
+		}
+		if s.Dragons[pt.pID][pt.id].HP == 0 {
+			continue
+		}
+		for _, d := range defenders {
+			if s.checkHostile(p, d.target()) != nil {
+				continue
+			}
+			res = append(res, DeclareAttack{Loc{Permanent, int(p), pt.id}, 0, d})
+		}
+	}
+	return
+}
+
+func (s State) legalActivations(p playerID) (res []Action) {
+	for _, pt := range s.sortedPermTargets() {
+		perm := s.Permanents[pt]
+		if pt.pID != p || perm.Activated {
+			continue
+		}
+		if len(s.cardEffects(perm.CName).Activate) > 0 {
+			res = append(res, Activate{int(pt.pID), pt.id})
+		}
+	}
+	return
+}
+
+// Spells can't target wizards protected by Bubublius. Dead wizards
+// can be targeted since some effects revive them, attacking them does
+// nothing so follow-up attacks skip them.
+func (s State) legalWizardTargets(e Effect) (res []Action) {
+	attacks := slices.ContainsFunc(e.Then, func(then Effect) bool {
+		return then.Op == "strike" || then.Op == "repeat"
+	})
+	// The attacker can die to its own attack, then only Cancel is left
+	if c, err := s.cardFromTarget(s.awaiting.atkr); attacks && (err != nil || !c.Alive()) {
+		return nil
+	}
+	for p := range s.NumPlayers {
+		if s.eliminated[p] {
+			continue
+		}
+		for i, c := range s.Field[p] {
+			if attacks && !c.Alive() {
+				continue
+			}
+			t := target{pID: playerID(p), id: i}
+			if _, err := s.cardToCast(t); s.awaiting.spell && err != nil {
+				continue
+			}
+			if e.Hostile && s.checkHostile(s.CurrentPlayer, t) != nil {
+				continue
+			}
+			res = append(res, Target{p, i})
+		}
+	}
+	return
+}
+
+func (s State) legalPermTargets() (res []Action) {
+	for _, pt := range s.sortedPermTargets() {
+		if !s.eliminated[pt.pID] {
+			res = append(res, TargetPerm{int(pt.pID), pt.id})
+		}
+	}
+	return
+}
+
+func (s State) legalTargets() (res []Action) {
+	e, ok := s.awaitedEffect()
+	if !ok {
+		return nil
+	}
+
+	switch targetArea(e.Target) {
+	case Permanent:
+		return s.legalPermTargets()
+	case Deck:
+		seen := map[CardName]bool{}
+		for _, c := range s.Players[s.CurrentPlayer].deck {
+			if !seen[c] {
+				seen[c] = true
+				res = append(res, TargetDeck{c})
+			}
+		}
+		slices.SortFunc(res, func(a, b Action) int {
+			return int(a.(TargetDeck).Card) - int(b.(TargetDeck).Card)
+		})
+		return res
+	}
+	return s.legalWizardTargets(e)
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func Test_LegalActions(t *testing.T) {
+	newGame := func() State {
+		g := testGame([]CardName{Librarian, Shieldmancer, Mortician, Meteorus}, []CardName{Angel})
+		g.Players[0].Hand = []CardName{Pyromancer, PyrusBalio}
+		return g
+	}
+
+	g := newGame()
+	if a := g.LegalActions(1); a != nil {
+		t.Errorf("expected no actions for the waiting player, got %v", a)
+	}
+
+	actions := g.LegalActions(0)
+	if !slices.Contains(actions, Action(Activate{0, 0})) {
+		t.Error("expected Meteorus to be activatable")
+	}
+	if slices.Contains(actions, Action(PlayFromHand{0})) {
+		t.Error("wizard can't be played onto a full field")
+	}
+	if !slices.Contains(actions, Action(PlayFromHand{1})) {
+		t.Error("expected spell to be playable")
+	}
+
+	for _, a := range actions {
+		if _, err := newGame().Apply(a); err != nil {
+			t.Errorf("legal action %v failed: %v", a.Args(), err)
+		}
+	}
+
+	g, _ = g.Apply(PlayFromHand{1})
+	targets := g.LegalActions(0)
+	if len(targets) != 5 || !slices.Contains(targets, Action(Cancel{})) {
+		t.Errorf("expected 4 targets and cancel, got %v", targets)
+	}
+}
+
+func Test_LegalActionsMana(t *testing.T) {
+	g, _ := newGame(2, DefaultRules())
+	g.Players[0].Hand = []CardName{PyrusBalio}
+	g = g.setMana(0)
+
+	if slices.Contains(g.LegalActions(0), Action(PlayFromHand{0})) {
+		t.Error("spell shouldn't be affordable")
+	}
+
+	g.Players[0].discountSpell = true
+	if !slices.Contains(g.LegalActions(0), Action(PlayFromHand{0})) {
+		t.Error("expected discounted spell to be affordable")
+	}
+}
+
+// Plays random games checking that every generated action is accepted.
+// The three player games run out of cards so players get eliminated.
+func Test_LegalActionsApply(t *testing.T) {
+	rules := DefaultRules()
+	rules.DeckOutLoses = true
+	for seed := range uint64(40) {
+		g := seededGame(seed)
+		if seed%2 == 1 {
+			g, _ = newGame(3, rules)
+			g = g.SetSeed(seed)
+			for p := range 3 {
+				g.Players[p].deck = []CardName{Librarian, Bloodeater, Pyromancer,
+					PyrusBalio, Meteorus, Dragonius, Mortician, Retrievio, Cancelio}
+			}
+			g = g.Start(cards)
+		}
+		r := rand.New(rand.NewSource(int64(seed)))
+		for step := 0; step < 300 && !g.GameOver(); step++ {
+			actions := g.LegalActions(g.CurrentPlayer)
+			if len(actions) == 0 {
+				t.Fatalf("seed %d step %d: no legal actions", seed, step)
+			}
+			for _, a := range actions {
+				if _, err := g.Apply(a); err != nil {
+					t.Fatalf("seed %d step %d: %T %v failed: %v", seed, step, a, a.Args(), err)
+				}
+			}
+			g, _ = g.Apply(actions[r.Intn(len(actions))])
+		}
+	}
+
+	// Blood Eater's second attack waits for a target
+	g := testGame([]CardName{Bloodeater}, []CardName{Librarian, Angel}, []CardName{Librarian})
+	g.Field[1][1].HP = 0
+	g = g.eliminate(2)
+	g, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 0}, 0, Loc{Wizard, 1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(g.LegalActions(0), Action(Target{1, 1})) {
+		t.Error("expected dead wizards not to be offered for attacks")
+	}
+	for _, a := range g.LegalActions(0) {
+		if _, err := g.Apply(a); err != nil {
+			t.Errorf("%T %v failed: %v", a, a.Args(), err)
+		}
+	}
+}
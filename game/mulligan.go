@@ -0,0 +1,69 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Each player gets one mulligan or keep, in turn order, before the first
+// turn starts. The current player is whoever is deciding.
+func (s State) mulligan(indices []int) (State, error) {
+	if s.phase != MulliganPhase {
+		return s, GameErr{"Not in the mulligan phase"}
+	}
+	p := s.CurrentPlayer
+	hand := s.Players[p].Hand
+
+	if len(indices) == 0 {
+		indices = make([]int, len(hand))
+		for i := range hand {
+			indices[i] = i
+		}
+	} else if s.Rules.Mulligan == MulliganFull {
+		return s, InputErr{"Only the whole hand can be redrawn"}
+	}
+
+	seen := map[int]bool{}
+	for _, i := range indices {
+		if i < 0 || i >= len(hand) {
+			return s, InputErr{"Hand index out of bounds"}
+		}
+		if seen[i] {
+			return s, InputErr{fmt.Sprintf("Hand index %d given twice", i)}
+		}
+		seen[i] = true
+	}
+
+	// Remove from the back so the indices stay valid
+	indices = slices.Clone(indices)
+	slices.Sort(indices)
+	for _, i := range slices.Backward(indices) {
+		s.Players[p].deck = append(s.Players[p].deck, hand[i])
+		hand = slices.Delete(hand, i, i+1)
+	}
+	s.Players[p].Hand = hand
+
+	s = s.shuffleDeck(p)
+	s.emit(Mulliganed{p, len(indices)})
+	s = s.drawCards(p, len(indices))
+	return s.nextMulligan(), nil
+}
+
+func (s State) keep() (State, error) {
+	if s.phase != MulliganPhase {
+		return s, GameErr{"Not in the mulligan phase"}
+	}
+	s.emit(Mulliganed{s.CurrentPlayer, 0})
+	return s.nextMulligan(), nil
+}
+
+// Passes the decision on, the first turn starts once everyone is done
+func (s State) nextMulligan() State {
+	if next := int(s.CurrentPlayer) + 1; next < s.NumPlayers {
+		s.CurrentPlayer = playerID(next)
+		s.emit(MulliganOffered{playerID(next)})
+		return s
+	}
+	s.CurrentPlayer = 0
+	return s.startTurn()
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_Mulligan(t *testing.T) {
+	rules := DefaultRules()
+	rules.Mulligan = MulliganPartial
+	g := phaseGame(rules, Librarian, Angel, Pyromancer)
+	if g.Phase() != MulliganPhase || g.CurrentPlayer != 0 {
+		t.Fatalf("expected player 0 to mulligan got %s %d", g.Phase(), g.CurrentPlayer)
+	}
+	if _, err := g.Apply(EndTurn{}); err != MulliganFirstErr {
+		t.Errorf("expected MulliganFirstErr got %v", err)
+	}
+	if n := len(g.LegalActions(0)); n != 1<<5 {
+		t.Errorf("expected keep and 31 mulligans got %d", n)
+	}
+	if _, err := g.Apply(Mulligan{[]int{1, 1}}); err == nil {
+		t.Error("expected repeated indices to fail")
+	}
+
+	kept := g.Players[0].Hand[1]
+	again, err := g.Execute(cards, "mulligan", "0", "2", "3", "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := g.Apply(Mulligan{[]int{4, 0, 3, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(again.Players[0].Hand, next.Players[0].Hand) {
+		t.Error("expected the mulligan to be deterministic")
+	}
+	g = next
+	if len(g.Players[0].Hand) != 5 || g.Players[0].Hand[0] != kept {
+		t.Errorf("expected %s kept in a 5 card hand got %v", kept, g.Players[0].Hand)
+	}
+	if len(g.Players[0].deck) != 17-5 {
+		t.Errorf("expected the deck size to be unchanged got %d", len(g.Players[0].deck))
+	}
+	if g.CurrentPlayer != 1 || g.Phase() != MulliganPhase {
+		t.Fatal("expected player 1 to mulligan next")
+	}
+
+	g, err = g.Apply(Keep{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.CurrentPlayer != 0 || g.Phase() != MainPhase {
+		t.Errorf("expected player 0's first turn got %s %d", g.Phase(), g.CurrentPlayer)
+	}
+	if _, err := g.Apply(Keep{}); err == nil {
+		t.Error("expected keep to fail after the mulligan phase")
+	}
+
+	rules.Mulligan = MulliganFull
+	g = phaseGame(rules, Librarian, Angel, Pyromancer)
+	if _, err := g.Apply(Mulligan{[]int{0}}); err == nil {
+		t.Error("expected partial mulligans to fail with the full rule")
+	}
+	if n := len(g.LegalActions(0)); n != 2 {
+		t.Errorf("expected keep and mulligan got %d", n)
+	}
+}
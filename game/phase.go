@@ -0,0 +1,81 @@
+package game
+
+import "fmt"
+
+type Phase int
+
+// Main is the zero value so games that haven't started can be set up
+// freely.
+const (
+	MainPhase   Phase = iota // cards can be played
+	StartPhase               // drawing and start of turn effects
+	CombatPhase              // after the first attack, see Rules.CombatLocksPlays
+	EndPhase                 // end of turn effects
+	MulliganPhase            // before the first turn, see State.mulligan
+)
+
+var phaseNames = [...]string{"main", "start", "combat", "end", "mulligan"}
+
+func (p Phase) String() string {
+	if p < 0 || int(p) >= len(phaseNames) {
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+	return phaseNames[p]
+}
+
+func (s State) Phase() Phase {
+	return s.phase
+}
+
+// Phases aren't enforced in test mode
+func (s State) inPhase(phases ...Phase) error {
+	if s.Testing {
+		return nil
+	}
+	for _, p := range phases {
+		if s.phase == p {
+			return nil
+		}
+	}
+	return GameErr{fmt.Sprintf("Can't do that during the %s phase", s.phase)}
+}
+
+// Cards can be played after attacking unless the rules say otherwise
+func (s State) canPlay() error {
+	if s.Rules.CombatLocksPlays {
+		return s.inPhase(MainPhase)
+	}
+	return s.inPhase(MainPhase, CombatPhase)
+}
+
+// Whether the wizard at t can declare an attack this turn. Dragons only
+// have Dragonius' once per turn limit.
+func (s State) canAttack(t target) error {
+	if err := s.inPhase(MainPhase, CombatPhase); err != nil {
+		return err
+	}
+	if s.Testing || t.area != Wizard {
+		return nil
+	}
+	c, err := s.cardFromTarget(t)
+	if err != nil {
+		return err
+	}
+	if c.sick && s.Rules.SummoningSickness {
+		return GameErr{"Wizards can't attack the turn they're played"}
+	}
+	if limit := s.Rules.AttacksPerTurn; limit > 0 && c.attacks >= limit {
+		return GameErr{fmt.Sprintf("%s has already attacked this turn", c.CName)}
+	}
+	return nil
+}
+
+// Attacks declared this turn
+func (c Card) Attacks() int {
+	return c.attacks
+}
+
+// Played since its owner's last turn started
+func (c Card) Sick() bool {
+	return c.sick
+}
@@ -0,0 +1,105 @@
+package game
+
+import "testing"
+
+// The turn structure rules are off by default
+func phaseRules() GameRules {
+	rules := DefaultRules()
+	rules.AttacksPerTurn = 1
+	rules.SummoningSickness = true
+	rules.CombatLocksPlays = true
+	return rules
+}
+
+func Test_AttackLimits(t *testing.T) {
+	g := phaseGame(phaseRules(), Librarian, Angel, Pyromancer)
+	if g.Phase() != MainPhase {
+		t.Fatalf("expected main phase got %s", g.Phase())
+	}
+
+	g, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 2}, 0, Loc{Wizard, 1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Phase() != CombatPhase {
+		t.Errorf("expected combat phase got %s", g.Phase())
+	}
+	if _, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 2}, 1, Loc{Wizard, 1, 0}}); err == nil {
+		t.Error("expected wizard to only attack once per turn")
+	}
+	if _, err := g.Apply(PlayFromHand{0}); err == nil {
+		t.Error("expected cards to not be playable after attacking")
+	}
+	for _, a := range g.LegalActions(0) {
+		switch a := a.(type) {
+		case PlayFromHand:
+			t.Errorf("expected no legal plays in combat, got %v", a.Args())
+		case DeclareAttack:
+			if a.Attacker.ID == 2 {
+				t.Errorf("expected wizard that attacked to have no legal attacks")
+			}
+		}
+	}
+	if _, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 1}, 0, Loc{Wizard, 0, 0}}); err != nil {
+		t.Errorf("expected other wizards to still attack: %v", err)
+	}
+
+	g, _ = g.Apply(EndTurn{})
+	g, _ = g.Apply(EndTurn{})
+	if _, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 2}, 0, Loc{Wizard, 1, 0}}); err != nil {
+		t.Errorf("expected attacks to reset next turn: %v", err)
+	}
+}
+
+func Test_SummoningSickness(t *testing.T) {
+	rules := phaseRules()
+	rules.MaxWizards = 2
+	g := phaseGame(rules, Librarian, Angel)
+	g.Players[0].Hand = append(g.Players[0].Hand, Pyromancer)
+
+	g, err := g.Apply(PlayFromHand{len(g.Players[0].Hand) - 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	atk := DeclareAttack{Loc{Wizard, 0, 2}, 0, Loc{Wizard, 1, 0}}
+	if _, err := g.Apply(atk); err == nil {
+		t.Error("expected new wizard to not attack the turn it was played")
+	}
+
+	g, _ = g.Apply(EndTurn{})
+	g, _ = g.Apply(EndTurn{})
+	if _, err := g.Apply(atk); err != nil {
+		t.Errorf("expected wizard to attack on its owner's next turn: %v", err)
+	}
+}
+
+func Test_PhaseRulesOptIn(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxWizards = 2
+	g := phaseGame(rules, Librarian, Angel)
+	g.Players[0].Hand = append(g.Players[0].Hand, Pyromancer)
+
+	g, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 0}, 0, Loc{Wizard, 1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 0}, 0, Loc{Wizard, 1, 1}}); err != nil {
+		t.Errorf("expected wizards to attack more than once by default: %v", err)
+	}
+	g = g.setMana(9)
+	if _, err := g.Apply(PlayFromHand{len(g.Players[0].Hand) - 1}); err != nil {
+		t.Errorf("expected cards to be playable after attacking by default: %v", err)
+	}
+}
+
+func Test_AttackTwiceIgnoresLimit(t *testing.T) {
+	g := phaseGame(phaseRules(), Librarian, Angel, Bloodeater)
+
+	g, err := g.Apply(DeclareAttack{Loc{Wizard, 0, 2}, 0, Loc{Wizard, 1, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Apply(Target{1, 1}); err != nil {
+		t.Errorf("expected attackTwice's second attack to be allowed: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package game
+
+import "math/rand/v2"
+
+// Every random decision goes through the State's own generator so that a
+// game can be reproduced from its seed and the commands played.
+
+func (s State) Seed() uint64 {
+	return s.seed
+}
+
+// Restarts the generator, call before Start to replay a game
+func (s State) SetSeed(seed uint64) State {
+	s.seed = seed
+	s.rng = *rand.NewPCG(seed, seed)
+	return s
+}
+
+// The returned Rand advances s.rng, so s must be returned by the caller
+func (s *State) rand() *rand.Rand {
+	return rand.New(&s.rng)
+}
+
+func (s State) shuffleDeck(p playerID) State {
+	d := s.Players[p].deck
+	s.rand().Shuffle(len(d), func(i, j int) {
+		d[i], d[j] = d[j], d[i]
+	})
+	return s
+}
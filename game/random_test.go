@@ -0,0 +1,43 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_SeededStart(t *testing.T) {
+	g1, g2 := seededGame(42), seededGame(42)
+	for p := range 2 {
+		if !slices.Equal(g1.Players[p].Hand, g2.Players[p].Hand) ||
+			!slices.Equal(g1.Players[p].deck, g2.Players[p].deck) {
+			t.Errorf("player %d: same seed gave different decks", p)
+		}
+	}
+
+	if g1.Seed() != 42 {
+		t.Errorf("expected seed 42 got %d", g1.Seed())
+	}
+}
+
+func Test_SeededMeteorus(t *testing.T) {
+	newGame := func() State {
+		g := testGame([]CardName{Librarian, Angel, Mortician, Meteorus},
+			[]CardName{Librarian, Angel, Mortician})
+		return g.SetSeed(7)
+	}
+
+	g1, g2 := newGame(), newGame()
+	for range 5 {
+		g1, _ = g1.activatePerm(PermTarget{0, 0})
+		g2, _ = g2.activatePerm(PermTarget{0, 0})
+		g1, _ = g1.endTurn()
+		g2, _ = g2.endTurn()
+	}
+	for p := range 2 {
+		for i := range 3 {
+			if g1.CardHp(p, i) != g2.CardHp(p, i) {
+				t.Errorf("wizard %d %d: Meteorus hit differently with the same seed", p, i)
+			}
+		}
+	}
+}
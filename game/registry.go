@@ -0,0 +1,215 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Cards are known by stable string IDs. Built in cards keep the numbers
+// the engine refers to them by, cards from other sets are numbered after
+// them in the order they're first loaded. Those numbers can change
+// between runs so decks and saves store IDs.
+type registry struct {
+	mu    sync.RWMutex
+	ids   []string // by CardName
+	names []string
+	byID  map[string]CardName
+	sets  []CardSet
+}
+
+var builtinCards = [...]struct{ id, name string }{
+	None:         {"", "None"},
+	Librarian:    {"librarian", "Librarian"},
+	Magician:     {"magician", "Magician"},
+	Shieldmancer: {"shieldmancer", "Shieldmancer"},
+	MindMage:     {"mind-mage", "MindMage"},
+	Angel:        {"angel", "Angel"},
+	Pyromancer:   {"pyromancer", "Pyromancer"},
+	Bloodeater:   {"blood-eater", "Bloodeater"},
+	Conjurer:     {"conjurer", "Conjurer"},
+	Mortician:    {"mortician", "Mortician"},
+	Protectio:    {"protectio", "Protectio"},
+	PyrusBalio:   {"pyrus-balio", "PyrusBalio"},
+	Mortius:      {"mortius", "Mortius"},
+	Enhancius:    {"enhancius", "Enhancius"},
+	Dragonius:    {"dragonius", "Dragonius"},
+	Cancelio:     {"cancelio", "Cancelio"},
+	Conjorius:    {"conjorius", "Conjorius"},
+	AngeliDustio: {"angeli-dustio", "AngeliDustio"},
+	Vitalius:     {"vitalius", "Vitalius"},
+	Dralio:       {"dralio", "Dralio"},
+	Librarius:    {"librarius", "Librarius"},
+	Aquarius:     {"aquarius", "Aquarius"},
+	Bubublius:    {"bubublius", "Bubublius"},
+	Meteorus:     {"meteorus", "Meteorus"},
+	Armorius:     {"armorius", "Armorius"},
+	DracusPyrio:  {"dracus-pyrio", "DracusPyrio"},
+	Retrievio:    {"retrievio", "Retrievio"},
+	Extractio:    {"extractio", "Extractio"},
+}
+
+var cardRegistry = newRegistry()
+
+func newRegistry() *registry {
+	r := &registry{
+		byID: map[string]CardName{},
+		sets: []CardSet{{ID: DefaultSet, Name: "Core Set", Kind: CoreSet}},
+	}
+	for n, c := range builtinCards {
+		r.ids = append(r.ids, c.id)
+		r.names = append(r.names, c.name)
+		if c.id != "" {
+			r.byID[c.id] = CardName(n)
+		}
+	}
+	return r
+}
+
+// Returns the card's number, giving it the next free one if the ID is new
+func RegisterCard(id, name string) CardName {
+	r := cardRegistry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := r.byID[id]; ok {
+		return n
+	}
+	n := CardName(len(r.ids))
+	r.ids = append(r.ids, id)
+	r.names = append(r.names, name)
+	r.byID[id] = n
+	return n
+}
+
+func CardByID(id string) (CardName, bool) {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n, ok := r.byID[id]
+	return n, ok
+}
+
+func (c CardName) registered() bool {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return c > None && int(c) < len(r.ids)
+}
+
+func (c CardName) ID() string {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if c < 0 || int(c) >= len(r.ids) {
+		return ""
+	}
+	return r.ids[c]
+}
+
+func (c CardName) String() string {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if c < 0 || int(c) >= len(r.names) {
+		return "CardName(" + strconv.Itoa(int(c)) + ")"
+	}
+	return r.names[c]
+}
+
+// Saved as the ID, numbers from older saves are still read
+func (c CardName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.ID())
+}
+
+func (c *CardName) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if n != int(None) && !CardName(n).registered() {
+			return fmt.Errorf("Unknown card %d", n)
+		}
+		*c = CardName(n)
+		return nil
+	}
+
+	var id string
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	if id == "" {
+		*c = None
+		return nil
+	}
+	n2, ok := CardByID(id)
+	if !ok {
+		return fmt.Errorf("Unknown card %q", id)
+	}
+	*c = n2
+	return nil
+}
+
+// Card name or number as written in decks and commands
+func ParseCardName(s string) (CardName, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if !CardName(n).registered() {
+			return None, InvalidCardErr
+		}
+		return CardName(n), nil
+	}
+	n, ok := CardByID(s)
+	if !ok {
+		return None, DeckError{fmt.Sprintf("Unknown card %q", s)}
+	}
+	return n, nil
+}
+
+// "Mind Mage" -> "mind-mage", used when card data has no id
+func cardID(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// Loads one or more card set files. Each card ends up at index
+// CName-1, numbers without a card in these sets are left empty.
+func LoadCards(sets ...[]byte) ([]Cdata, error) {
+	var cards []Cdata
+	for _, data := range sets {
+		set, err := parseSetFile(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range set.Cards {
+			if c.Set == "" {
+				c.Set = set.Set.ID
+			}
+			if c.ID == "" {
+				c.ID = cardID(c.Name)
+			}
+			if c.ID == "" {
+				return nil, fmt.Errorf("Card without a name or id")
+			}
+			c.CName = RegisterCard(c.ID, c.Name)
+
+			i := int(c.CName) - 1
+			if i >= len(cards) {
+				cards = append(cards, make([]Cdata, i-len(cards)+1)...)
+			}
+			if cards[i].CName != None {
+				return nil, fmt.Errorf("Card %q is defined twice", c.ID)
+			}
+			cards[i] = c
+		}
+	}
+	return cards, nil
+}
+
+// The card's data if it was loaded
+func LookupCard(cards []Cdata, n CardName) (Cdata, bool) {
+	i := int(n) - 1
+	if i < 0 || i >= len(cards) || cards[i].CName != n {
+		return Cdata{}, false
+	}
+	return cards[i], true
+}
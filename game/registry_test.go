@@ -0,0 +1,72 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+var expansion = []byte(`{
+	"set": {"id": "expansion-1", "name": "Expansion 1", "kind": "expansion", "released": "2024-06-01"},
+	"cards": [
+	{"id": "fire-imp", "name": "Fire Imp", "type": "wizard", "hp": 6,
+		"atk1": {"name": "scorch", "desc": "Do 2 damage", "dmg": 2},
+		"atk2": {"name": "burn", "desc": "Poison the target", "dmg": 0},
+		"effects": {"atk1": [{"op": "hit"}], "atk2": [{"op": "status", "status": "poisoned"}]}},
+	{"name": "Ember Rain", "type": "instant", "hp": 2,
+		"effects": {"play": [{"op": "damage", "target": "random", "amount": 1}]}}
+]}`)
+
+var promo = []byte(`{
+	"set": {"id": "promo", "name": "Promo", "kind": "promo"},
+	"cards": [
+	{"id": "golden-librarian", "name": "Golden Librarian", "type": "wizard", "hp": 8,
+		"atk1": {"name": "study", "desc": "Draw 1 card", "dmg": 1},
+		"atk2": {"name": "tome", "desc": "Do 1 damage", "dmg": 1},
+		"effects": {"atk1": [{"op": "draw", "amount": 1}, {"op": "hit"}]}}
+]}`)
+
+func Test_CardRegistry(t *testing.T) {
+	all, err := LoadCards(data, expansion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp, ok := CardByID("fire-imp")
+	if !ok || imp <= Extractio {
+		t.Fatalf("expected fire-imp after the built in cards got %d", imp)
+	}
+	rain, ok := CardByID("ember-rain")
+	if !ok || rain == imp {
+		t.Fatal("expected cards without an id to use their name")
+	}
+	if imp.String() != "Fire Imp" || imp.ID() != "fire-imp" || PyrusBalio.ID() != "pyrus-balio" {
+		t.Errorf("unexpected names %s %s %s", imp, imp.ID(), PyrusBalio.ID())
+	}
+	if c, ok := LookupCard(all, imp); !ok || c.Hp != 6 {
+		t.Error("expected fire-imp to be loaded")
+	}
+	if _, ok := LookupCard(cards, imp); ok {
+		t.Error("expected fire-imp to be missing from the base set")
+	}
+
+	again, err := LoadCards(expansion, data)
+	if err != nil || !reflect.DeepEqual(again, all) {
+		t.Error("expected numbers to stay the same whatever the load order")
+	}
+	if _, err := LoadCards(data, data); err == nil {
+		t.Error("expected duplicate cards to fail")
+	}
+
+	g := testGame([]CardName{Librarian}, []CardName{Angel})
+	g = g.SetCardData(all)
+	g, err = g.Apply(Create{imp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err = g.attack(target{pID: 0, id: 1, atkNum: 1}, target{pID: 1, id: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Field[1][0].HasStatus(Poisoned) {
+		t.Error("expected the expansion card's effects to run")
+	}
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// Bump when the replay format changes.
+const ReplayVersion = 1
+
+// A Replay is enough to rebuild a game: the setup before Start and
+// every command that was successfully passed to Execute afterwards.
+type Replay struct {
+	Version    int          `json:"version"`
+	CardData   string       `json:"cardData"`
+	Seed       uint64       `json:"seed"`
+	Rules      GameRules    `json:"rules"`
+	NumPlayers int          `json:"numPlayers"`
+	Testing    bool         `json:"testing,omitempty"`
+	Teams      []int        `json:"teams,omitempty"`
+	Decks      [][]CardName `json:"decks"`
+	Commands   [][]string   `json:"commands"`
+}
+
+// Fingerprint of the card data so replays aren't run against
+// cards that changed since they were recorded.
+func CardDataVersion(cards []Cdata) string {
+	data, err := json.Marshal(cards)
+	if err != nil {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Call before Start, the decks and seed are recorded as they are.
+func NewReplay(s State, cards []Cdata) Replay {
+	r := Replay{
+		Version:    ReplayVersion,
+		CardData:   CardDataVersion(cards),
+		Seed:       s.Seed(),
+		Rules:      s.Rules,
+		NumPlayers: s.NumPlayers,
+		Testing:    s.Testing,
+	}
+	for p := range s.NumPlayers {
+		r.Decks = append(r.Decks, slices.Clone(s.Players[p].deck))
+		r.Teams = append(r.Teams, s.Players[p].Team)
+	}
+	return r
+}
+
+// Commands are kept in their canonical form, see canonicalArgs
+func (r *Replay) Record(args ...string) {
+	r.Commands = append(r.Commands, canonicalArgs(args))
+}
+
+func (r Replay) Len() int {
+	return len(r.Commands)
+}
+
+// The state right after Start, before any commands
+func (r Replay) Initial(cards []Cdata) (State, error) {
+	if r.Version < 1 || r.Version > ReplayVersion {
+		return State{}, fmt.Errorf("Unsupported replay version %d", r.Version)
+	}
+	if r.CardData != CardDataVersion(cards) {
+		return State{}, fmt.Errorf("Replay was recorded with different card data")
+	}
+	if len(r.Decks) != r.NumPlayers {
+		return State{}, fmt.Errorf("Replay has %d decks for %d players",
+			len(r.Decks), r.NumPlayers)
+	}
+
+	s, err := NewGame(r.NumPlayers, r.Rules)
+	if err != nil {
+		return State{}, err
+	}
+	// same setup as NewTestGame
+	if r.Testing {
+		s.Testing = true
+		s = s.startTurn()
+	}
+	if len(r.Teams) > 0 {
+		if s, err = s.SetTeams(r.Teams...); err != nil {
+			return State{}, err
+		}
+	}
+	s = s.SetSeed(r.Seed)
+	for p, deck := range r.Decks {
+		s.Players[p].deck = slices.Clone(deck)
+	}
+	return s.Start(cards), nil
+}
+
+// The state after the first n commands
+func (r Replay) StateAt(cards []Cdata, n int) (State, error) {
+	if n < 0 || n > r.Len() {
+		return State{}, fmt.Errorf("Replay step %d out of range", n)
+	}
+	s, err := r.Initial(cards)
+	if err != nil {
+		return State{}, err
+	}
+	for i, args := range r.Commands[:n] {
+		if s, err = s.Execute(cards, args...); err != nil {
+			return State{}, fmt.Errorf("Replay command %d (%v): %w", i, args, err)
+		}
+	}
+	return s, nil
+}
+
+// Drops every command after the first n
+func (r *Replay) Truncate(n int) {
+	if n >= 0 && n < len(r.Commands) {
+		r.Commands = r.Commands[:n]
+	}
+}
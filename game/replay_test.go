@@ -0,0 +1,51 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_Replay(t *testing.T) {
+	g := deckGame(2, DefaultRules(), 3, Librarian, Angel, Pyromancer)
+	r := NewReplay(g, cards)
+	g = g.Start(cards)
+
+	states := []State{g}
+	for _, cmd := range [][]string{
+		{"attack", "0", "0", "0", "1", "0"},
+		{"end"},
+		{"attack", "1", "1", "0", "0", "0"},
+		{"end"},
+	} {
+		next, err := g.Execute(cards, cmd...)
+		if err != nil {
+			t.Fatalf("%v: %s", cmd, err)
+		}
+		g = next
+		r.Record(cmd...)
+		states = append(states, g)
+	}
+
+	for i, expected := range states {
+		s, err := r.StateAt(cards, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("step %d: replayed state differs", i)
+		}
+	}
+
+	other := slices.Clone(cards)
+	other[0].Hp++
+	if _, err := r.Initial(other); err == nil {
+		t.Error("expected error for different card data")
+	}
+
+	r.Record("create", fmt.Sprint(int(Angel)))
+	if cmd := r.Commands[len(r.Commands)-1]; !slices.Equal(cmd, []string{"create", "angel"}) {
+		t.Errorf("expected cards to be recorded by ID, got %v", cmd)
+	}
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// GameRules holds every number the engine plays by. DefaultRules are the
+// standard rules; change a copy of them for house rules or quick formats.
+type GameRules struct {
+	CardsDrawnAtStart int  `json:"cardsDrawnAtStart"`
+	MaxHp             int  `json:"maxHp"`
+	ManaMax           int  `json:"manaMax"`
+	MaxDeckLength     int  `json:"maxDeckLength"`
+	MaxWizards        int  `json:"maxWizards"`
+	MaxCopies         int  `json:"maxCopies"`
+	MaxFieldLen       int  `json:"maxFieldLen"`
+	DeckOutLoses      bool `json:"deckOutLoses"`
+	// Attacks each wizard can declare a turn, 0 for no limit
+	AttacksPerTurn int `json:"attacksPerTurn"`
+	// Extra cards have to be discarded at the end of the turn
+	MaxHandSize int          `json:"maxHandSize"`
+	Overdraw    OverdrawRule `json:"overdraw"`
+	// Redraws allowed before the first turn
+	Mulligan MulliganRule `json:"mulligan"`
+	// Splash damage, Meteorus and attacks can hit teammates
+	FriendlyFire bool `json:"friendlyFire"`
+	// Wizards can't attack until their owner's next turn
+	SummoningSickness bool `json:"summoningSickness"`
+	// No more cards can be played after attacking
+	CombatLocksPlays bool `json:"combatLocksPlays"`
+	// Which card sets decks may use, see Formats
+	Format string `json:"format"`
+
+	// Cards
+	CardPerDmg       int `json:"cardPerDmg"`
+	DisappearRecoil  int `json:"disappearRecoil"`
+	AllyRecoil       int `json:"allyRecoil"`
+	MegaSplashDmg    int `json:"megaSplashDmg"`
+	ResistanceBlock  int `json:"resistanceBlock"`
+	PyrusBalioDmg    int `json:"pyrusBalioDmg"`
+	EnhanciusBuff    int `json:"enhanciusBuff"`
+	MortiusDmg       int `json:"mortiusDmg"`
+	DragonHp         int `json:"dragonHp"`
+	DragoniusDmg     int `json:"dragoniusDmg"`
+	AngeliDustioHeal int `json:"angeliDustioHeal"`
+	VitaliusBuff     int `json:"vitaliusBuff"`
+	DralioDraw       int `json:"dralioDraw"`
+	MeteorusDmg      int `json:"meteorusDmg"`
+	ArmoriusBlock    int `json:"armoriusBlock"`
+	DracusPyrioDmg   int `json:"dracusPyrioDmg"`
+}
+
+func DefaultRules() GameRules {
+	return GameRules{
+		CardsDrawnAtStart: 5,
+		MaxHp:             8,
+		ManaMax:           6,
+		MaxDeckLength:     20,
+		MaxWizards:        3,
+		MaxCopies:         4,
+		MaxFieldLen:       3,
+		MaxHandSize:       7,
+		Overdraw:          OverdrawKeep,
+		Mulligan:          MulliganNone,
+		Format:            "open",
+
+		CardPerDmg:       2,
+		DisappearRecoil:  2,
+		AllyRecoil:       1,
+		MegaSplashDmg:    1,
+		ResistanceBlock:  1,
+		PyrusBalioDmg:    1,
+		EnhanciusBuff:    1,
+		MortiusDmg:       2,
+		DragonHp:         3,
+		DragoniusDmg:     3,
+		AngeliDustioHeal: 2,
+		VitaliusBuff:     2,
+		DralioDraw:       2,
+		MeteorusDmg:      1,
+		ArmoriusBlock:    1,
+		DracusPyrioDmg:   7,
+	}
+}
+
+// What happens when a player draws with a full hand
+type OverdrawRule string
+
+const (
+	OverdrawKeep OverdrawRule = "keep" // draw anyway, discard down at the end of the turn
+	OverdrawBurn OverdrawRule = "burn" // the card goes straight to the discard pile
+	OverdrawSkip OverdrawRule = "skip" // the card stays in the deck
+)
+
+var overdrawRules = []OverdrawRule{OverdrawKeep, OverdrawBurn, OverdrawSkip}
+
+// How players may redraw their opening hand
+type MulliganRule string
+
+const (
+	MulliganNone    MulliganRule = "none"    // no mulligan phase
+	MulliganFull    MulliganRule = "full"    // the whole hand is redrawn
+	MulliganPartial MulliganRule = "partial" // any chosen cards are redrawn
+)
+
+var mulliganRules = []MulliganRule{MulliganNone, MulliganFull, MulliganPartial}
+
+var InvalidRulesErr = errors.New("Invalid rules")
+
+// The most the board can show: two digits of HP on a card, and the field
+// and hand rows are 8 cards wide
+const (
+	HpLimit       = 99
+	FieldLenLimit = 5
+	HandSizeLimit = 8
+)
+
+func (r GameRules) Validate() error {
+	switch {
+	case r.MaxHp < 1, r.ManaMax < 1, r.CardPerDmg < 1:
+		return InvalidRulesErr
+	case r.MaxHp > HpLimit, r.MaxFieldLen > FieldLenLimit, r.MaxHandSize > HandSizeLimit:
+		return InvalidRulesErr
+	case r.MaxWizards < 1 || r.MaxWizards > r.MaxFieldLen:
+		return InvalidRulesErr
+	case r.MaxDeckLength < r.MaxWizards, r.MaxCopies < 1:
+		return InvalidRulesErr
+	case r.CardsDrawnAtStart < 0, r.AttacksPerTurn < 0, r.MaxHandSize < 1:
+		return InvalidRulesErr
+	case !slices.Contains(overdrawRules, r.Overdraw):
+		return InvalidRulesErr
+	case !slices.Contains(mulliganRules, r.Mulligan):
+		return InvalidRulesErr
+	}
+	if _, ok := LookupFormat(r.Format); !ok {
+		return InvalidRulesErr
+	}
+	return nil
+}
+
+// Fields missing from data keep their default value
+func ParseRules(data []byte) (GameRules, error) {
+	r := DefaultRules()
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, err
+	}
+	return r, r.Validate()
+}
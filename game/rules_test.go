@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+func Test_Rules(t *testing.T) {
+	r := DefaultRules()
+	r.MaxHp = 12
+	r.PyrusBalioDmg = 4
+	r.MaxFieldLen = 4
+
+	g, err := newGame(2, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Testing = true
+	g = g.playCards(0, Librarian, Angel, Mortician, Pyromancer)
+	g = g.DoDmg(0, 0, -10)
+	g.checkHpIs(t, 12)
+
+	g = g.playCards(0, PyrusBalio)
+	g, _ = g.target(target{pID: 0, id: 0})
+	g.checkHpIs(t, 8)
+
+	r.MaxWizards = 5
+	if _, err := newGame(2, r); err == nil {
+		t.Error("expected more wizards than field slots to be invalid")
+	}
+
+	for _, f := range []func(*GameRules){
+		func(r *GameRules) { r.MaxHp = HpLimit + 1 },
+		func(r *GameRules) { r.MaxFieldLen = FieldLenLimit + 1 },
+		func(r *GameRules) { r.MaxHandSize = HandSizeLimit + 1 },
+	} {
+		r := DefaultRules()
+		f(&r)
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected rules past what the board shows to be invalid: %+v", r)
+		}
+	}
+}
+
+func Test_ParseRules(t *testing.T) {
+	r, err := ParseRules([]byte(`{"maxHp": 10, "deckOutLoses": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DefaultRules()
+	expected.MaxHp = 10
+	expected.DeckOutLoses = true
+	if r != expected {
+		t.Errorf("expected %v got %v", expected, r)
+	}
+
+	if _, err := ParseRules([]byte(`{"maxHp": 0}`)); err == nil {
+		t.Error("expected error for invalid rules")
+	}
+}
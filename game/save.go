@@ -0,0 +1,376 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Bump when the saved format changes. Older saves are still loaded.
+//
+// 2: Card statuses replaced the protected and resistance flags
+// 3: Players have teams
+// 4: Cards are saved by their ID instead of their number
+// 5: Awaits record who is waiting for a target
+const SaveVersion = 5
+
+type savedTarget struct {
+	PID    playerID `json:"pid"`
+	Area   cardType `json:"area"`
+	ID     int      `json:"id"`
+	AtkNum int      `json:"atkNum"`
+}
+
+type savedCard struct {
+	HP       int      `json:"hp"`
+	CName    CardName `json:"card"`
+	Atk0     Attack   `json:"atk0"`
+	Atk1     Attack   `json:"atk1"`
+	Statuses []Status `json:"statuses,omitempty"`
+	Attached CardName `json:"attached,omitempty"`
+	Attacks  int      `json:"attacks,omitempty"`
+	Sick     bool     `json:"sick,omitempty"`
+
+	// Version 1
+	Protected  bool `json:"protected,omitempty"`
+	Resistance bool `json:"resistance,omitempty"`
+}
+
+type savedPerm struct {
+	PID        playerID    `json:"pid"`
+	ID         int         `json:"id"`
+	CName      CardName    `json:"card"`
+	Cost       int         `json:"cost"`
+	Activated  bool        `json:"activated,omitempty"`
+	AttachedTo savedTarget `json:"attachedTo"`
+}
+
+type savedPlayer struct {
+	Name           string     `json:"name"`
+	ID             playerID   `json:"id"`
+	Team           int        `json:"team"`
+	Hand           []CardName `json:"hand"`
+	Discard        []CardName `json:"discard"`
+	Deck           []CardName `json:"deck"`
+	ManaCap        int        `json:"manaCap"`
+	MagicianHealth int        `json:"magicianHealth"`
+	MoreMana       int        `json:"moreMana"`
+	DiscountSpell  bool       `json:"discountSpell,omitempty"`
+}
+
+type savedAwait struct {
+	IsTrue    bool        `json:"isTrue"`
+	Atkr      savedTarget `json:"atkr"`
+	Spell     bool        `json:"spell,omitempty"`
+	SpellName CardName    `json:"spellName,omitempty"`
+	Perm      savedTarget `json:"perm"`
+	Caster    playerID    `json:"caster,omitempty"`
+
+	Discarding bool `json:"discarding,omitempty"`
+
+	Paid       int  `json:"paid,omitempty"`
+	Discounted bool `json:"discounted,omitempty"`
+	FromHand   bool `json:"fromHand,omitempty"`
+}
+
+type savedMessage struct {
+	Text     string          `json:"text"`
+	Private  bool            `json:"private,omitempty"`
+	Receiver playerID        `json:"receiver,omitempty"`
+	Kind     string          `json:"kind,omitempty"`
+	Event    json.RawMessage `json:"event,omitempty"`
+}
+
+type savedState struct {
+	Version       int                     `json:"version"`
+	NumPlayers    int                     `json:"numPlayers"`
+	Players       [MaxPlayers]savedPlayer `json:"players"`
+	CurrentPlayer playerID                `json:"currentPlayer"`
+	Field         [MaxPlayers][]savedCard `json:"field"`
+	Dragons       [MaxPlayers][]savedCard `json:"dragons"`
+	Permanents    []savedPerm             `json:"permanents"`
+	Mana          int                     `json:"mana"`
+	Rules         GameRules               `json:"rules"`
+	Testing       bool                    `json:"testing,omitempty"`
+	Phase         Phase                   `json:"phase,omitempty"`
+	Awaiting      savedAwait              `json:"awaiting"`
+	Seed          uint64                  `json:"seed"`
+	RNG           []byte                  `json:"rng"`
+	Eliminated    [MaxPlayers]bool        `json:"eliminated"`
+	GameOver      bool                    `json:"gameOver,omitempty"`
+	Winner        playerID                `json:"winner,omitempty"`
+	Log           []savedMessage          `json:"log"`
+}
+
+func decodeEvent[T Event](data []byte) (Event, error) {
+	var e T
+	err := json.Unmarshal(data, &e)
+	return e, err
+}
+
+var eventDecoders = map[string]func([]byte) (Event, error){
+	"TurnStarted":      decodeEvent[TurnStarted],
+	"CardDrawn":        decodeEvent[CardDrawn],
+	"CardsSeen":        decodeEvent[CardsSeen],
+	"CardPlayed":       decodeEvent[CardPlayed],
+	"DamageDealt":      decodeEvent[DamageDealt],
+	"Healed":           decodeEvent[Healed],
+	"WizardDied":       decodeEvent[WizardDied],
+	"PermAttached":     decodeEvent[PermAttached],
+	"PermRemoved":      decodeEvent[PermRemoved],
+	"StatusApplied":    decodeEvent[StatusApplied],
+	"StatusExpired":    decodeEvent[StatusExpired],
+	"ManaChanged":      decodeEvent[ManaChanged],
+	"PlayerEliminated": decodeEvent[PlayerEliminated],
+	"GameEnded":        decodeEvent[GameEnded],
+	"EffectMessage":    decodeEvent[EffectMessage],
+	"Attacked":         decodeEvent[Attacked],
+	"Avenged":          decodeEvent[Avenged],
+	"PassiveUsed":      decodeEvent[PassiveUsed],
+	"ManaGiven":        decodeEvent[ManaGiven],
+	"PermRetrieved":    decodeEvent[PermRetrieved],
+	"CardTutored":      decodeEvent[CardTutored],
+	"CardDiscarded":    decodeEvent[CardDiscarded],
+	"HandDiscarded":    decodeEvent[HandDiscarded],
+	"DiscardRequired":  decodeEvent[DiscardRequired],
+	"DeckEmpty":        decodeEvent[DeckEmpty],
+	"MulliganOffered":  decodeEvent[MulliganOffered],
+	"Mulliganed":       decodeEvent[Mulliganed],
+	"Cancelled":        decodeEvent[Cancelled],
+	"DeckShown":        decodeEvent[DeckShown],
+}
+
+func saveTarget(t target) savedTarget {
+	return savedTarget{t.pID, t.area, t.id, t.atkNum}
+}
+
+func (t savedTarget) load() target {
+	return target{pID: t.PID, area: t.Area, id: t.ID, atkNum: t.AtkNum}
+}
+
+func saveCards(cards []Card) []savedCard {
+	if cards == nil {
+		return nil
+	}
+	res := make([]savedCard, len(cards))
+	for i, c := range cards {
+		res[i] = savedCard{
+			HP:       c.HP,
+			CName:    c.CName,
+			Atk0:     c.Atk0,
+			Atk1:     c.Atk1,
+			Statuses: c.Statuses(),
+			Attached: c.attached,
+			Attacks:  c.attacks,
+			Sick:     c.sick,
+		}
+	}
+	return res
+}
+
+func loadCards(saved []savedCard, capacity int) []Card {
+	if saved == nil {
+		return nil
+	}
+	res := make([]Card, len(saved), max(len(saved), capacity))
+	for i, c := range saved {
+		res[i] = Card{
+			HP:       c.HP,
+			CName:    c.CName,
+			Atk0:     c.Atk0,
+			Atk1:     c.Atk1,
+			attached: c.Attached,
+			attacks:  c.Attacks,
+			sick:     c.Sick,
+		}
+		for _, st := range c.Statuses {
+			if st.Kind >= 0 && st.Kind < numStatusKinds {
+				res[i].statuses[st.Kind] = st
+			}
+		}
+		if c.Protected {
+			res[i].statuses[Protected] = Status{Protected, 1, 1}
+		}
+		if c.Resistance {
+			res[i].statuses[Resistance] = Status{Resistance, 1, 1}
+		}
+	}
+	return res
+}
+
+func (s State) MarshalJSON() ([]byte, error) {
+	rng, err := s.rng.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	saved := savedState{
+		Version:       SaveVersion,
+		NumPlayers:    s.NumPlayers,
+		CurrentPlayer: s.CurrentPlayer,
+		Mana:          s.Mana,
+		Rules:         s.Rules,
+		Testing:       s.Testing,
+		Phase:         s.phase,
+		Seed:          s.seed,
+		RNG:           rng,
+		Eliminated:    s.eliminated,
+		GameOver:      s.gameOver,
+		Winner:        s.winner,
+		Awaiting: savedAwait{
+			IsTrue:    s.awaiting.isTrue,
+			Atkr:      saveTarget(s.awaiting.atkr),
+			Spell:     s.awaiting.spell,
+			SpellName: s.awaiting.spellName,
+			Perm:      savedTarget{PID: s.awaiting.perm.pID, ID: s.awaiting.perm.id},
+			Caster:    s.awaiting.caster,
+
+			Discarding: s.awaiting.discarding,
+			Paid:       s.awaiting.paid,
+			Discounted: s.awaiting.discounted,
+			FromHand:   s.awaiting.fromHand,
+		},
+	}
+
+	for i, p := range s.Players {
+		saved.Players[i] = savedPlayer{
+			p.Name, p.ID, p.Team, p.Hand, p.Discard, p.deck,
+			p.manaCap, p.magicianHealth, p.moreMana, p.discountSpell,
+		}
+		saved.Field[i] = saveCards(s.Field[i])
+		saved.Dragons[i] = saveCards(s.Dragons[i])
+	}
+
+	for _, pt := range s.sortedPermTargets() {
+		p := s.Permanents[pt]
+		saved.Permanents = append(saved.Permanents, savedPerm{
+			pt.pID, pt.id, p.CName, p.Cost, p.Activated, saveTarget(p.AttachedTo),
+		})
+	}
+
+	for _, msg := range s.Output.Messages {
+		m := savedMessage{Text: msg.Text, Private: msg.Private, Receiver: msg.Receiver}
+		if msg.Event != nil {
+			m.Kind = reflect.TypeOf(msg.Event).Name()
+			if m.Event, err = json.Marshal(msg.Event); err != nil {
+				return nil, err
+			}
+		}
+		saved.Log = append(saved.Log, m)
+	}
+
+	return json.Marshal(saved)
+}
+
+// Card data isn't saved, use SetCardData after loading
+func (s *State) UnmarshalJSON(data []byte) error {
+	// Rules added after the save was made keep their default value
+	saved := savedState{Rules: DefaultRules()}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	if saved.Version < 1 || saved.Version > SaveVersion {
+		return fmt.Errorf("Unsupported save version %d", saved.Version)
+	}
+	if saved.NumPlayers < 2 || saved.NumPlayers > MaxPlayers {
+		return errors.New("Invalid number of players")
+	}
+
+	res := State{
+		NumPlayers:    saved.NumPlayers,
+		CurrentPlayer: saved.CurrentPlayer,
+		Permanents:    make(map[PermTarget]Perm),
+		Mana:          saved.Mana,
+		Rules:         saved.Rules,
+		Testing:       saved.Testing,
+		phase:         saved.Phase,
+		seed:          saved.Seed,
+		eliminated:    saved.Eliminated,
+		gameOver:      saved.GameOver,
+		winner:        saved.Winner,
+		awaiting: Await{
+			isTrue:    saved.Awaiting.IsTrue,
+			atkr:      saved.Awaiting.Atkr.load(),
+			spell:     saved.Awaiting.Spell,
+			spellName: saved.Awaiting.SpellName,
+			perm:      PermTarget{saved.Awaiting.Perm.PID, saved.Awaiting.Perm.ID},
+			caster:    saved.Awaiting.Caster,
+
+			discarding: saved.Awaiting.Discarding,
+			paid:       saved.Awaiting.Paid,
+			discounted: saved.Awaiting.Discounted,
+			fromHand:   saved.Awaiting.FromHand,
+		},
+	}
+	if saved.Version < 5 {
+		res.awaiting.caster = saved.CurrentPlayer
+	}
+	if err := res.rng.UnmarshalBinary(saved.RNG); err != nil {
+		return err
+	}
+
+	for i, p := range saved.Players {
+		res.Players[i] = Player{
+			Name:           p.Name,
+			ID:             p.ID,
+			Team:           p.Team,
+			Hand:           p.Hand,
+			Discard:        p.Discard,
+			deck:           p.Deck,
+			manaCap:        p.ManaCap,
+			magicianHealth: p.MagicianHealth,
+			moreMana:       p.MoreMana,
+			discountSpell:  p.DiscountSpell,
+		}
+		if saved.Version < 3 {
+			res.Players[i].Team = i
+		}
+		res.Field[i] = loadCards(saved.Field[i], saved.Rules.MaxFieldLen)
+		res.Dragons[i] = loadCards(saved.Dragons[i], MaxPermLen)
+	}
+
+	for _, p := range saved.Permanents {
+		pt := PermTarget{p.PID, p.ID}
+		if err := res.checkPlayerID(pt.pID); err != nil || pt.id < 0 || pt.id >= MaxPermLen {
+			return fmt.Errorf("Invalid permanent %d %d", pt.pID, pt.id)
+		}
+		res.Permanents[pt] = Perm{
+			CName:      p.CName,
+			Cost:       p.Cost,
+			Activated:  p.Activated,
+			AttachedTo: p.AttachedTo.load(),
+		}
+	}
+
+	for _, m := range saved.Log {
+		msg := Message{Text: m.Text, Private: m.Private, Receiver: m.Receiver}
+		if m.Kind != "" {
+			decode, ok := eventDecoders[m.Kind]
+			if !ok {
+				return fmt.Errorf("Unknown event %s", m.Kind)
+			}
+			e, err := decode(m.Event)
+			if err != nil {
+				return err
+			}
+			msg.Event = e
+		}
+		res.Output.Messages = append(res.Output.Messages, msg)
+	}
+
+	*s = res
+	return nil
+}
+
+func (s State) Save() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func Load(data []byte, cards []Cdata) (State, error) {
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, err
+	}
+	return s.SetCardData(cards), nil
+}
@@ -0,0 +1,39 @@
+package game
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_SaveLoad(t *testing.T) {
+	g := seededGame(7)
+	g.Mana = 10
+	g = g.playCards(0, Aquarius)
+	g.Permanents[PermTarget{0, 1}] = Perm{CName: Enhancius, Cost: 2,
+		AttachedTo: target{pID: 0, area: Wizard, id: 1}}
+	g.Field[0][1].attached = Enhancius
+	g = g.addStatus(&g.Field[1][0], Protected)
+	g.awaiting = Await{isTrue: true, spell: true, spellName: Cancelio}
+
+	data, err := g.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(data, cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, loaded) {
+		t.Errorf("loaded state differs from saved state")
+	}
+
+	g, loaded = g.shuffleDeck(1), loaded.shuffleDeck(1)
+	if !slices.Equal(g.Players[1].deck, loaded.Players[1].deck) {
+		t.Error("loaded state shuffled differently")
+	}
+
+	if _, err := Load([]byte(`{"version": 99}`), cards); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
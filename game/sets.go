@@ -0,0 +1,131 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+type SetKind string
+
+const (
+	CoreSet      SetKind = "core"
+	ExpansionSet SetKind = "expansion"
+	PromoSet     SetKind = "promo"
+)
+
+// Cards without a set belong to the core set
+const DefaultSet = "core"
+
+type CardSet struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Kind     SetKind `json:"kind"`
+	Released string  `json:"released,omitempty"`
+	Desc     string  `json:"desc,omitempty"`
+}
+
+// A card set file, plain card lists are still read as the core set
+type cardSetFile struct {
+	Set   CardSet `json:"set"`
+	Cards []Cdata `json:"cards"`
+}
+
+// Sets are kept in the card registry in the order they're first loaded
+func RegisterSet(set CardSet) error {
+	if set.ID == "" {
+		return fmt.Errorf("Card set without an id")
+	}
+	if !slices.Contains(setKinds, set.Kind) {
+		return fmt.Errorf("Card set %q has unknown kind %q", set.ID, set.Kind)
+	}
+	if set.Name == "" {
+		set.Name = set.ID
+	}
+
+	r := cardRegistry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := slices.IndexFunc(r.sets, func(s CardSet) bool { return s.ID == set.ID }); i >= 0 {
+		r.sets[i] = set
+		return nil
+	}
+	r.sets = append(r.sets, set)
+	return nil
+}
+
+func LookupSet(id string) (CardSet, bool) {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sets {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return CardSet{}, false
+}
+
+func Sets() []CardSet {
+	r := cardRegistry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.sets)
+}
+
+var setKinds = []SetKind{CoreSet, ExpansionSet, PromoSet}
+
+// Reads either a list of cards or a set with its cards
+func parseSetFile(data []byte) (cardSetFile, error) {
+	var list []Cdata
+	if err := json.Unmarshal(data, &list); err == nil {
+		return cardSetFile{Set: CardSet{ID: DefaultSet}, Cards: list}, nil
+	}
+
+	var file cardSetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, err
+	}
+	if err := RegisterSet(file.Set); err != nil {
+		return file, err
+	}
+	return file, nil
+}
+
+// A format limits which sets decks can use. Formats with neither Sets nor
+// Kinds allow every card.
+type Format struct {
+	ID    string
+	Name  string
+	Sets  []string
+	Kinds []SetKind
+}
+
+var formats = []Format{
+	{ID: "open", Name: "Open"},
+	{ID: "standard", Name: "Standard", Kinds: []SetKind{CoreSet, ExpansionSet}},
+	{ID: "core", Name: "Core", Sets: []string{DefaultSet}},
+}
+
+func LookupFormat(id string) (Format, bool) {
+	i := slices.IndexFunc(formats, func(f Format) bool { return f.ID == id })
+	if i < 0 {
+		return Format{}, false
+	}
+	return formats[i], true
+}
+
+func Formats() []Format {
+	return slices.Clone(formats)
+}
+
+func (f Format) Allows(c Cdata) bool {
+	if len(f.Sets) == 0 && len(f.Kinds) == 0 {
+		return true
+	}
+	if slices.Contains(f.Sets, c.Set) {
+		return true
+	}
+	set, ok := LookupSet(c.Set)
+	return ok && slices.Contains(f.Kinds, set.Kind)
+}
@@ -0,0 +1,54 @@
+package game
+
+import "testing"
+
+func Test_CardSets(t *testing.T) {
+	all, err := LoadCards(data, expansion, promo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp, _ := CardByID("fire-imp")
+	golden, _ := CardByID("golden-librarian")
+	if c, _ := LookupCard(all, Librarian); c.Set != DefaultSet {
+		t.Errorf("expected plain card lists to be the core set got %q", c.Set)
+	}
+	if c, _ := LookupCard(all, imp); c.Set != "expansion-1" {
+		t.Errorf("expected fire-imp in expansion-1 got %q", c.Set)
+	}
+	if set, ok := LookupSet("expansion-1"); !ok || set.Name != "Expansion 1" || set.Kind != ExpansionSet {
+		t.Errorf("unexpected set %v", set)
+	}
+	if _, err := LoadCards([]byte(`{"set": {"id": "bad", "kind": "bootleg"}, "cards": []}`)); err == nil {
+		t.Error("expected unknown set kinds to fail")
+	}
+
+	deck := map[int]int{
+		int(Librarian):  1,
+		int(Angel):      1,
+		int(imp):        1,
+		int(PyrusBalio): 3,
+	}
+	r := DefaultRules()
+	for format, legal := range map[string]bool{"open": true, "standard": true, "core": false} {
+		r.Format = format
+		if err := ValidateDeck(r, all, deck); (err == nil) != legal {
+			t.Errorf("%s: expected legal %v got %v", format, legal, err)
+		}
+	}
+
+	deck[int(golden)] = 1
+	delete(deck, int(Librarian))
+	r.Format = "standard"
+	if err := ValidateDeck(r, all, deck); err == nil {
+		t.Error("expected promo cards to be illegal in standard")
+	}
+	r.Format = "open"
+	if err := ValidateDeck(r, all, deck); err != nil {
+		t.Error(err)
+	}
+
+	r.Format = "unknown"
+	if r.Validate() == nil {
+		t.Error("expected unknown formats to be invalid rules")
+	}
+}
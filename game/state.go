@@ -3,42 +3,30 @@ package game
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 	"slices"
 )
 
-const (
-	CardsDrawnAtStart= 5
-	CardPerDmg       = 2
-	MaxHp            = 8
-	DisappearRecoil  = 2
-	AllyRecoil       = 1
-	PyrusBalioDmg    = 1
-	EnhanciusBuff    = 1
-	DragoniusDmg     = 3
-	AngeliDustioHeal = 2
-	VitaliusBuff     = 2
-	MeteorusDmg      = 1
-	DracusPyrioDmg   = 7
-)
-
 func (s State) Start(cards []Cdata) State {
 	//todo
+	s = s.Clone()
+	s.cards = cards
 	for p := range s.NumPlayers {
 		slices.SortFunc(s.Players[p].deck, func (a, b CardName) int {
 			return int(a) - int(b)
 		})
-		for range MaxWizards {
+		for range s.Rules.MaxWizards {
 			s, _ = s.play(playerID(p), CardFromName(cards, s.Players[p].deck[0]))
 			s.Players[p].deck = slices.Delete(s.Players[p].deck, 0, 1)
 		}
 
-		rand.Shuffle(len(s.Players[p].deck), func(i, j int) {
-			s.Players[p].deck[i], s.Players[p].deck[j] = 
-			s.Players[p].deck[j], s.Players[p].deck[i]
-		})
+		s = s.shuffleDeck(playerID(p))
 
-		s = s.drawCards(playerID(p), CardsDrawnAtStart)
+		s = s.drawCards(playerID(p), s.Rules.CardsDrawnAtStart)
+	}
+	if s.Rules.Mulligan != MulliganNone {
+		s.phase = MulliganPhase
+		s.emit(MulliganOffered{s.CurrentPlayer})
+		return s
 	}
 	return s.startTurn()
 }
@@ -46,8 +34,19 @@ func (s State) Start(cards []Cdata) State {
 func (s State) startTurn() State {
 	p := &s.Players[s.CurrentPlayer]
 
+	s.phase = StartPhase
+	field := s.Field[s.CurrentPlayer]
+	for i := range field {
+		field[i].attacks = 0
+		field[i].sick = false
+	}
+
+	ctx := &triggerCtx{player: s.CurrentPlayer, manaMax: s.Rules.ManaMax}
+	s = s.fire(OnTurnStart, ctx)
+	p = &s.Players[s.CurrentPlayer]
+
 	mana := p.manaCap
-	manaMax := s.manaMax + s.numOfPerms(Aquarius)
+	manaMax := ctx.manaMax
 
 	if mana < manaMax {
 		p.manaCap++
@@ -56,23 +55,23 @@ func (s State) startTurn() State {
 	mana += p.moreMana
 	p.moreMana = 0
 
-	s.Mana = mana
-
-	field := &s.Field[s.CurrentPlayer]
-	for i := 0; i < len(*field); i++ {
-		(*field)[i].protected = false
-	}
-
-	s.Output.Printf("Player %v's turn", s.CurrentPlayer)
+	s.emit(TurnStarted{s.CurrentPlayer})
+	s = s.tickStatuses(s.CurrentPlayer, OnTurnStart)
+	p = &s.Players[s.CurrentPlayer]
+	s.setManaTo(mana)
 	if !s.Testing {
+		if s.Rules.DeckOutLoses && len(p.deck) == 0 {
+			s.emit(DeckEmpty{s.CurrentPlayer})
+			return s.eliminate(p.ID)
+		}
 		s = s.drawCards(p.ID, 1)
 	}
 
-	if numLib := s.numOfPerms(Librarius); numLib > 0 {
-		s.Output.Printf("Librarius activated")
-		s = s.drawCards(p.ID, numLib)
+	if ctx.draws > 0 {
+		s = s.drawCards(p.ID, ctx.draws)
 	}
 
+	s.phase = MainPhase
 	return s
 }
 
@@ -81,14 +80,14 @@ func CardFromName(cards []Cdata, n CardName) Playable {
 
 	if c.Type == "perm" {
 		return Perm{
-			Cost:  c.Hp,
+			Cost:  c.Cost,
 			CName: c.CName,
 		}
 	}
 
 	if c.Type == "instant" {
 		return Instant{
-			Cost:  c.Hp, // should be c.Cost
+			Cost:  c.Cost,
 			CName: c.CName,
 		}
 	}
@@ -107,82 +106,108 @@ func (s State) playPerm(player playerID, p Perm) (State, error) {
 		return s, err
 	}
 
-	switch p.CName {
-	case Mortius, Enhancius, Vitalius, Bubublius, Armorius: // Attachments
-		s = s.awaitPerm(pTarget)
-	case Dragonius:
-		c := Card{
-			CName: Dragonius,
-			HP:    3,
-			Atk0: Attack{
-				Name: "Dragon Breath",
-				Dmg:  3,
-			},
-		}
-		s.Dragons[player][s.lenPermsOf(player)-1] = c
-	case Aquarius, Conjorius, Librarius, Meteorus:
-	default:
-		return s, errors.New("unexpected perm")
+	ctx := &effectCtx{player: player, source: p.CName, perm: pTarget, spell: true}
+	s, err = s.runEffects(s.cardEffects(p.CName).Play, ctx)
+	if err != nil {
+		return s, err
 	}
-	s.Output.Printf("%s played %s", s.Players[s.CurrentPlayer], p.CName)
+	s.emit(CardPlayed{player, p.CName})
 	return s, nil
 }
 
+func (s State) spellCost(p playerID, c Playable) int {
+	cost := c.getCost()
+	if s.Players[p].discountSpell {
+		cost--
+	}
+	return cost
+}
+
+// Checks everything that could make play fail before it changes anything
+func (s State) checkPlayable(p playerID, c Playable) error {
+	switch c.getType() {
+	case Wizard:
+		if len(s.Field[p]) >= s.Rules.MaxFieldLen {
+			return errors.New("Field is at max capacity")
+		}
+		return nil
+	case Permanent:
+		if s.lenPermsOf(p) >= MaxPermLen {
+			return errors.New("Max number of perms reached")
+		}
+	}
+	if needs := s.cardEffects(c.getCardName()).Needs; needs != "" {
+		cond, ok := conditions[needs]
+		if !ok {
+			return ImplmtErr{fmt.Sprintf("Unknown condition %q", needs)}
+		}
+		if !cond(s, &effectCtx{player: p}) {
+			return GameErr{fmt.Sprintf("Can't play %s without %s", c.getCardName(), needs)}
+		}
+	}
+
+	if !s.Testing && s.Mana < s.spellCost(p, c) {
+		return errors.New("Not enough mana")
+	}
+	return nil
+}
+
 func (s State) play(p playerID, c Playable) (State, error) {
 	cardType := c.getType()
+	if err := s.checkPlayable(p, c); err != nil {
+		return s, err
+	}
 
 	if cardType == Wizard {
-		if len(s.Field[p]) == MaxFieldLen {
-			return s, errors.New("Field is at max capacity")
-		}
-
 		card := c.(Card)
 		if card.CName == Magician {
 			card.HP = s.Players[p].magicianHealth
 		}
+		card.sick = true
 		s.Field[p] = append(s.Field[p], card)
-		return s, nil
+		s.emit(CardPlayed{p, card.CName})
+		return s.fire(OnPlay, &triggerCtx{player: p, name: card.CName}), nil
 	}
 
+	paid, discounted := 0, false
 	if s.Testing == false {
-		cost := c.getCost()
-		if s.Players[p].discountSpell {
-			cost--
-			s.Players[p].discountSpell = false
-		}
-		if s.Mana < cost {
-			return s, errors.New("Not enough mana")
-		}
-		s.Mana -= cost
+		paid, discounted = s.spellCost(p, c), s.Players[p].discountSpell
+		s.setManaTo(s.Mana - paid)
+		s.Players[p].discountSpell = false
 	}
 
+	var err error
 	switch cardType {
 	case Permanent:
-		return s.playPerm(p, c.(Perm))
+		s, err = s.playPerm(p, c.(Perm))
 	case InstantSpell:
-		return s.playInstant(c.(Instant))
+		s, err = s.playInstant(c.(Instant))
 	}
-	return s, nil
+	if err != nil {
+		return s, err
+	}
+	if s.awaiting.spell {
+		s.awaiting.paid = paid
+		s.awaiting.discounted = discounted
+	}
+	return s.fire(OnPlay, &triggerCtx{player: p, name: c.getCardName()}), nil
 }
 
 func (s State) drawCards(p playerID, n int) State {
-	drawn := 0
+	drawn := []CardName{}
 	var err error
 	for range n {
 		s, err = s.drawCard(p)
 		if err == nil {
-			drawn++
+			hand := s.Players[p].Hand
+			drawn = append(drawn, hand[len(hand)-1])
 		}
 	}
-		
-	if drawn == 0 {
-		s.Output.Printf("%s couldn't draw from their empty deck", s.Players[p])
-	} else if drawn < n {
-		s.Output.Printf("%s drew %d card(s), their deck is now empty", s.Players[p], drawn)
-	} else {
-		s.Output.Printf("%s drew %d card(s)", s.Players[p], drawn)
-	}
 
+	s.emit(CardDrawn{p, len(drawn), n})
+	if len(drawn) > 0 {
+		s.emitPrivate(p, CardsSeen{p, drawn})
+	}
 	return s
 }
 
@@ -192,6 +217,17 @@ func (s State) drawCard(p playerID) (State, error) {
 	if len(d) == 0 {
 		return s, errors.New("Deck empty")
 	}
+	if len(player.Hand) >= s.Rules.MaxHandSize {
+		switch s.Rules.Overdraw {
+		case OverdrawBurn:
+			c := d[len(d)-1]
+			player.deck = d[:len(d)-1]
+			s.emit(CardDiscarded{p, c, true})
+			return s.discard(p, c), HandFullErr
+		case OverdrawSkip:
+			return s, HandFullErr
+		}
+	}
 	player.Hand = append(player.Hand, d[len(d)-1])
 	player.deck = d[:len(d)-1]
 	return s, nil
@@ -215,17 +251,44 @@ type target struct {
 	atkNum int
 }
 
+func (s State) nextPlayer() playerID {
+	next := s.CurrentPlayer
+	for range s.NumPlayers {
+		next = playerID(int(next+1) % s.NumPlayers)
+		if !s.eliminated[next] {
+			break
+		}
+	}
+	return next
+}
+
 func (s State) endTurn() (State, error) {
-	s.CurrentPlayer = playerID(int(s.CurrentPlayer+1) % s.NumPlayers)
+	if s.gameOver {
+		return s, GameOverErr
+	}
+	s = s.dropAwait()
+	if over := s.cardsOverLimit(); over > 0 && !s.Testing && !s.eliminated[s.CurrentPlayer] {
+		s.awaiting = Await{isTrue: true, discarding: true}
+		s.emit(DiscardRequired{s.CurrentPlayer, over})
+		return s, nil
+	}
+	s.phase = EndPhase
+	s = s.fire(OnTurnEnd, &triggerCtx{player: s.CurrentPlayer})
+	s = s.tickStatuses(s.CurrentPlayer, OnTurnEnd)
+	s.CurrentPlayer = s.nextPlayer()
 	for k, v := range s.Permanents {
 		v.Activated = false
 		s.Permanents[k] = v
 	}
-	return s.startTurn(), nil
+	s = s.startTurn().checkGameOver()
+	if !s.gameOver && s.eliminated[s.CurrentPlayer] {
+		return s.endTurn()
+	}
+	return s, nil
 }
 
 func (s State) setMana(n int) State {
-	s.Mana = n
+	s.setManaTo(n)
 	return s
 }
 
@@ -238,107 +301,113 @@ func (s State) activatePerm(pt PermTarget) (State, error) {
 	if p.Activated {
 		return s, errors.New("Perm alr activated")
 	}
-	p.Activated = true
-	s.Permanents[pt] = p
-
-	switch p.CName { 
-	case Meteorus:
-		t, err := s.randomTarget()
-		if err != nil {
-			return s, err
-		}
-
-		s.Output.Printf("%s did %d damage to %s's %s",
-			Meteorus, MeteorusDmg, s.Players[t.pID], s.Field[t.pID][t.id].CName)
-		return s.DoDmg(int(t.pID), t.id, MeteorusDmg), nil
-	case Dragonius:
-		s.awaiting = Await{
-			isTrue: true,
-			atkr: target{area: Permanent, pID: pt.pID, id: pt.id},
-		}
-		return s, nil	
+	if err := s.inPhase(MainPhase, CombatPhase); err != nil {
+		return s, err
 	}
-
-	return s, errors.New("not a valid perm name")
+	effects := s.cardEffects(p.CName).Activate
+	if len(effects) == 0 {
+		return s, errors.New("not a valid perm name")
+	}
+	next, err := s.runEffects(effects, &effectCtx{
+		player: pt.pID,
+		source: p.CName,
+		atkr:   target{area: Permanent, pID: pt.pID, id: pt.id},
+	})
+	if err != nil {
+		return s, err
+	}
+	// Only used up once it worked, the effects might have removed it
+	if p, ok := next.Permanents[pt]; ok {
+		p.Activated = true
+		next.Permanents[pt] = p
+	}
+	return next, nil
 }
 
 func (s State) showDeck(p playerID) State {
-	if len(s.Players[p].deck) == 0 {
-		s.Output.Printf("Your deck is empty")
-	}
-	for i, card := range s.Players[p].deck {
-		//s.Output.Printf("%d: %s", i, card.String()) 
-		s.Output.PrivatePrint(p, "%d: %s", i, card.String()) 
-	}
+	s.emitPrivate(p, DeckShown{p, slices.Clone(s.Players[p].deck), false})
 	return s
 }
 
+// Declared attacks count towards the attacker's limit and start the
+// combat phase. Extra attacks from abilities like attackTwice and frenzy
+// use strike directly.
 func (s State) attack(atkr, defr target) (State, error) {
+	if err := s.canAttack(atkr); err != nil {
+		return s, err
+	}
+	if err := s.checkHostile(atkr.pID, defr); err != nil {
+		return s, err
+	}
 	atkrCard, err := s.cardFromTarget(atkr)
 	if err != nil {
 		return s, err
 	}
 
-	defrCard, err := s.cardFromTarget(defr)
+	// Counted first since some attacks take the attacker off the field
+	atkrCard.attacks++
+	next, err := s.strike(atkr, defr)
 	if err != nil {
+		atkrCard.attacks--
 		return s, err
 	}
+	next.phase = CombatPhase
+	return next, nil
+}
 
-	atk, err := atkrCard.atk(atkr.atkNum)
-	if errors.Is(err, DragoniusAtkErr{}) {
-		d := PermTarget{atkr.pID, atkr.id} 
-		dr := s.Permanents[d]
-		if dr.Activated {
-			return s, errors.New("Dragonius can only attack once per turn")
-		}
-		dr.Activated = true
-		s.Permanents[d] = dr
-	} else if err != nil {
+func (s State) strike(atkr, defr target) (State, error) {
+	atkrCard, err := s.cardFromTarget(atkr)
+	if err != nil {
 		return s, err
 	}
 
-	sideEffect, ok := atkSideEffects[atk.Name]
-	if ok {
-		s = sideEffect(s, atkr, defr)
+	if _, err := s.cardFromTarget(defr); err != nil {
+		return s, err
 	}
 
-	dmg := s.baseDamage(atkr, atk)
-
-	if atk.Name == "bypass" {
-		s = s.doRawDmg(defrCard, dmg)
-		return s, nil
+	if !atkrCard.Alive() {
+		return s, GameErr{"Dead wizards can't attack"}
+	}
+	if atkrCard.HasStatus(Stunned) {
+		return s, GameErr{"Stunned wizards can't attack"}
+	}
+	if atkr.atkNum == 1 && atkrCard.HasStatus(Silenced) {
+		return s, GameErr{"Silenced wizards can only use their first attack"}
 	}
 
-	defrWasAlive := defrCard.HP > 0
-	s = s.DoDmgToCard(defrCard, dmg)
-	s.Output.Printf("%s's %s attacked %s's %s", 
-		s.Players[atkr.pID],
-		atkrCard.CName.String(),
-		s.Players[defr.pID],
-		defrCard.CName.String())
-
-	if defrWasAlive && defrCard.HP == 0 {
-		if atk.Name == "frenzy" {
-			s.Output.Printf("%s can attack again", atkrCard.CName)
-			s = s.setAwait(atkr)
-		}
-		if defrCard.attached == Mortius {
-			s.Output.Printf("%s's Mortius attacked %s", defrCard.CName, atkrCard.CName)
-			s = s.DoDmgToCard(atkrCard, 2)
+	atk, err := atkrCard.atk(atkr.atkNum)
+	dragon := PermTarget{atkr.pID, atkr.id}
+	isDragon := errors.Is(err, DragoniusAtkErr{})
+	if isDragon {
+		if s.Permanents[dragon].Activated {
+			return s, errors.New("Dragonius can only attack once per turn")
 		}
+	} else if err != nil {
+		return s, err
 	}
 
-	afterEffect, ok := SideEffectsAfterAtk[atk.Name]
-	if ok {
-		s = afterEffect(s, atkr, defr)
+	// Run on a copy so a failed attack leaves s as it was
+	next, err := s.Clone().runEffects(s.attackEffects(atkrCard, atkr.atkNum), &effectCtx{
+		player: atkr.pID,
+		source: atkrCard.CName,
+		atkr:   atkr,
+		defr:   defr,
+		dmg:    s.baseDamage(atkr, atk),
+	})
+	if err != nil {
+		return s, err
 	}
-	
-	return s, nil
+	// Only used up once it worked, the dragon might have died attacking
+	if p, ok := next.Permanents[dragon]; isDragon && ok {
+		p.Activated = true
+		next.Permanents[dragon] = p
+	}
+	return next, nil
 }
 
 func (s State) allAllies(f func(*Card) bool, t target) bool {
-	for i := 0; i < MaxFieldLen-1; i++ {
-		fId := (t.id + 1 + i) % MaxFieldLen
+	for i := 0; i < s.Rules.MaxFieldLen-1; i++ {
+		fId := (t.id + 1 + i) % s.Rules.MaxFieldLen
 		card, err := s.cardFromTarget(target{pID: t.pID, id: fId})
 		if err != nil {
 			continue
@@ -350,15 +419,25 @@ func (s State) allAllies(f func(*Card) bool, t target) bool {
 	return true
 }
 
+// Allies are the other wizards on t's field, then its teammates' wizards
 func (s State) applyToAllies(f func(*Card), t target) {
-	for i := 0; i < MaxFieldLen-1; i++ {
-		fId := (t.id + 1 + i) % MaxFieldLen
+	for i := 0; i < s.Rules.MaxFieldLen-1; i++ {
+		fId := (t.id + 1 + i) % s.Rules.MaxFieldLen
 		card, err := s.cardFromTarget(target{pID: t.pID, id: fId})
 		if err != nil {
 			continue
 		}
 		f(card)
 	}
+	for _, p := range s.Teammates(t.pID) {
+		for i := range s.Field[p] {
+			card, err := s.cardFromTarget(target{pID: p, id: i})
+			if err != nil {
+				continue
+			}
+			f(card)
+		}
+	}
 }
 
 func (g State) DoDmg(p, id, dmg int) State {
@@ -366,27 +445,37 @@ func (g State) DoDmg(p, id, dmg int) State {
 }
 
 func (g State) DoDmgToCard(c *Card, dmg int) State {
+	return g.dealDmg(None, c, dmg)
+}
+
+// Damage from source that can be reduced or prevented by effects
+func (g State) dealDmg(source CardName, c *Card, dmg int) State {
+	prevented := 0
 	if dmg > 0 {
-		if c.protected {
-			dmg = 0
+		if c.HasStatus(Protected) {
+			prevented = dmg
 		} else {
-			if c.resistance {
-				// constants
-				dmg--
+			if c.HasStatus(Resistance) {
+				prevented += g.Rules.ResistanceBlock
 			}
 			if c.attached == Armorius {
-				dmg--
+				prevented += g.Rules.ArmoriusBlock
 			}
+			prevented = min(prevented, dmg)
 		}
 	}
 
-	return g.doRawDmg(c, dmg)
+	return g.applyDmg(source, c, dmg-prevented, prevented)
 }
 
 func (g State) doRawDmg(c *Card, dmg int) State {
-	maxH := MaxHp
+	return g.applyDmg(None, c, dmg, 0)
+}
+
+func (g State) applyDmg(source CardName, c *Card, dmg, prevented int) State {
+	maxH := g.Rules.MaxHp
 	if c.attached == Vitalius {
-		maxH += VitaliusBuff
+		maxH += g.Rules.VitaliusBuff
 	}
 
 	newHp := c.HP - dmg
@@ -396,38 +485,25 @@ func (g State) doRawDmg(c *Card, dmg int) State {
 		newHp = maxH
 	}
 
-	if c.HP > 0 && newHp == 0 {
-		for i := range g.Players {
-			n := g.numOfPermsOf(playerID(i), Conjorius)
-			for range n {
-				g.Output.Printf("Conjorius gave one mana to %s", g.Players[i].Name) 
-			}
-  			g.Players[i].moreMana += n 
-		}
+	loc := g.locOf(c)
+	if dmg > 0 || prevented > 0 {
+		g.emit(DamageDealt{source, loc, c.CName, dmg, prevented})
+	} else if newHp > c.HP {
+		g.emit(Healed{loc, c.CName, newHp - c.HP})
 	}
-	c.HP = newHp
 
-	return g
-}
-
-func (s State) expectedTargetType() cardType {
-	atkr := s.awaiting.atkr
-	atkrCard := &s.Field[atkr.pID][atkr.id]
-
-	name := atkrCard.Atk0.Name
-	if atkr.atkNum == 1 {
-		name = atkrCard.Atk1.Name
-	}
+	died := c.HP > 0 && newHp == 0
+	c.HP = newHp
 
-	targetsPerm := []string{
-		"removePerm",
+	ctx := &triggerCtx{player: playerID(loc.PID), card: c, amount: dmg}
+	if dmg > 0 {
+		g = g.fire(OnDamaged, ctx)
 	}
-
-	if slices.Contains(targetsPerm, name) {
-		return Permanent
+	if died {
+		g = g.fire(OnDeath, ctx)
+		g = g.die(loc, c)
 	}
-
-	return Wizard
+	return g
 }
 
 func (s State) cardFromTarget(t target) (*Card, error) {
@@ -448,157 +524,174 @@ func (s State) cardFromTarget(t target) (*Card, error) {
 	return &s.Field[t.pID][t.id], nil
 }
 
-func (s State) spellTarget(defr target) (State, error) {
-	spell, ok := castSpellOnTarget[s.awaiting.spellName]
-	if !ok {
-		return s, errors.New("Invalid spell name")
-	}
-
-	return func() (State, error) {
-		s, e := spell(s, defr)
-		if e == nil {
-			s = s.cancelAwait()
-		}
-		return s, e
-	}()
-}
-
 func (s State) printCardsInDeck() State {
-	cards := map[CardName]bool{}
+	var cards []CardName
 	for _, cn := range s.Players[s.CurrentPlayer].deck {
-		cards[cn] = true	
-	}
-	for c := range cards {
-		s.Output.PrivatePrint(s.CurrentPlayer, "Id: %d - %s", c, c)
+		if !slices.Contains(cards, cn) {
+			cards = append(cards, cn)
+		}
 	}
+	s.emitPrivate(s.CurrentPlayer, DeckShown{s.CurrentPlayer, cards, true})
 	return s
 }
 
+// Runs the awaited effect's Then on defr
 func (s State) target(defr target) (State, error) {
 	if !s.awaiting.isTrue {
 		return s, errors.New("Not awaiting target")
 	}
-
-	if s.awaiting.spell {
-		return s.spellTarget(defr)
-	}
-
-	targetType := s.expectedTargetType()
-	if defr.area != targetType {
-		return s, errors.New("Unexpected target")
+	if s.awaiting.discarding {
+		return s, DiscardFirstErr
 	}
-	if err := s.checkTarget(defr); err != nil {
-		return s, err
+	e, ok := s.awaitedEffect()
+	if !ok {
+		return s, ImplmtErr{"Nothing to target with"}
 	}
 
-	atkr := s.awaiting.atkr
-	atkrCard, err := s.cardFromTarget(atkr)
-	if err != nil {
-		return s, err
+	if defr.area != targetArea(e.Target) {
+		return s, errors.New("Unexpected target")
 	}
-
-	defrCard, wrongTargetError := s.cardFromTarget(defr)
-	atk, err := atkrCard.atk(atkr.atkNum)
-
-	if errors.Is(err, DragoniusAtkErr{}) {
-		if wrongTargetError != nil {
-			return s, errors.New("invalid target")
+	if defr.area != Deck {
+		if err := s.checkTarget(defr); err != nil {
+			return s, err
 		}
-		s = s.cancelAwait()
-		s.Output.Printf("Dragonius attacked %s", defrCard.CName)
-		return s.DoDmgToCard(defrCard, DragoniusDmg), nil
-	}
-	if err != nil {
-		return s, err
 	}
 
-	if targetType == Permanent {
-		switch atk.Name {
-		case "removePerm":
-			s = s.cancelAwait()	
-			return s.removePerm(PermTarget{defr.pID, defr.id})
-		}
-		return s, errors.New("Unexpected attack")
-	}
-
-	switch atk.Name {
-	case "revive":
-		atkrCard.HP = 0
-		defrCard.HP = MaxHp
-	case "attackTwice":
-		s, err := s.attack(
-			target{pID: atkr.pID, id: atkr.id, atkNum: 2},
-			defr)
-		s = s.cancelAwait()
-		return s, err
-	case "frenzy":
-		defrWasAlive := defrCard.HP > 0
-
-		newS, err := s.attack(atkr, defr)
-
+	a := s.awaiting
+	ctx := &effectCtx{
+		player: s.CurrentPlayer,
+		source: a.spellName,
+		atkr:   a.atkr,
+		defr:   defr,
+		perm:   a.perm,
+		spell:  a.spell,
+	}
+	if !a.spell {
+		atkrCard, err := s.cardFromTarget(a.atkr)
 		if err != nil {
-			return s, nil 
+			return s, err
 		}
-
-		if defrWasAlive && defrCard.HP == 0 {
-			return newS, nil
+		ctx.player, ctx.source = a.atkr.pID, atkrCard.CName
+	} else if defr.area == Wizard {
+		if _, err := s.cardToCast(defr); err != nil {
+			return s, err
+		}
+	}
+	if e.Hostile {
+		if err := s.checkHostile(ctx.player, defr); err != nil {
+			return s, err
 		}
-
-		newS = newS.cancelAwait() 
-		return newS, nil
 	}
 
 	s = s.cancelAwait()
+	s, err := s.runEffects(e.Then, ctx)
+	if err != nil {
+		return s, err
+	}
+	if a.spell && !s.isPerm(a.perm, a.spellName) {
+		s = s.discard(ctx.player, a.spellName)
+	}
 	return s, nil
 }
 
+// Whether the perm at pt is name, for telling attachments from instants
+func (s State) isPerm(pt PermTarget, name CardName) bool {
+	p, ok := s.Permanents[pt]
+	return ok && p.CName == name
+}
+
 func (s State) setAwait(a target) State {
 	s.awaiting = Await{
 		isTrue: true,
 		atkr:   a,
+		caster: a.pID,
 	}
 	return s
 }
 
-func (s State) awaitSpell(spell Playable) State {
-	s.awaiting = Await{
-		isTrue:    true,
-		spell:     true,
-		spellName: spell.getCardName(),
-	}
+func (s State) AwaitStatus() string {
+	return fmt.Sprintf("%t %s Atkr: %v", s.awaiting.isTrue, s.awaiting.spellName.String(), s.awaiting.atkr) 
+}
+
+func (s State) cancelAwait() State {
+	s.awaiting = Await{}
 	return s
 }
 
-func (s State) AwaitStatus() string {
-	return fmt.Sprintf("%t %s Atkr: %v", s.awaiting.isTrue, s.awaiting.spellName.String(), s.awaiting.atkr) 
+// Spells that did something before asking for a target, like Extractio
+// showing the deck, can't be taken back
+func (s State) cancellable() bool {
+	a := s.awaiting
+	if !a.isTrue || a.discarding {
+		return false
+	}
+	if !a.spell {
+		return true
+	}
+	play := s.cardEffects(a.spellName).Play
+	return len(play) > 0 && play[0].Op == "await"
 }
 
-func (s State) awaitPerm(pt PermTarget) State {
-	p, ok := s.Permanents[pt]
-	if !ok {
-		panic("COuldnt find perm to await")
+// Backs out of the pending target. Spells and attachments go back to
+// the hand with their mana refunded, attack follow-ups are skipped.
+func (s State) cancel() (State, error) {
+	a := s.awaiting
+	if !a.isTrue {
+		return s, GameErr{"Nothing to cancel"}
 	}
-	s.awaiting = Await{
-		isTrue:    true,
-		spell:     true,
-		spellName: p.getCardName(),
-		perm:      pt,
+	if !s.cancellable() {
+		return s, GameErr{"Can't cancel after the deck was revealed"}
 	}
-	return s
+	if !a.spell {
+		s.emit(Cancelled{a.caster, None})
+		return s.cancelAwait(), nil
+	}
+
+	if s.isPerm(a.perm, a.spellName) {
+		// Attachment that hasn't been attached to anything yet
+		delete(s.Permanents, a.perm)
+		s.emit(PermRemoved{a.perm.pID, a.spellName})
+	}
+
+	p := &s.Players[a.caster]
+	if a.fromHand {
+		p.Hand = append(p.Hand, a.spellName)
+	}
+	p.discountSpell = p.discountSpell || a.discounted
+	s = s.cancelAwait()
+	if a.paid > 0 {
+		s.setManaTo(s.Mana + a.paid)
+	}
+	s.emit(Cancelled{a.caster, a.spellName})
+	return s, nil
 }
 
-func (s State) cancelAwait() State {
-	s.awaiting = Await{}
+// Pending targets don't carry over to the next turn. Spells that can
+// still be taken back are refunded, the rest go to the discard pile.
+func (s State) dropAwait() State {
+	a := s.awaiting
+	if !a.isTrue || a.discarding {
+		return s
+	}
+	if s.cancellable() {
+		s, _ = s.cancel()
+		return s
+	}
+	s = s.cancelAwait()
+	if a.spell && !s.isPerm(a.perm, a.spellName) {
+		s = s.discard(a.caster, a.spellName)
+	}
 	return s
 }
 
-func (s State) randomTarget() (target, error) {
+// Any living wizard except src's teammates, unless Rules.FriendlyFire
+func (s State) randomTarget(src playerID) (State, target, error) {
 	targets := []target{}
 	for p := range s.Players {
-		for i := range MaxFieldLen {
+		for i := range s.Rules.MaxFieldLen {
 			t := target{pID: playerID(p), id: i}
 			c, err := s.cardFromTarget(t)
-			if err != nil {
+			if err != nil || s.checkHostile(src, t) != nil {
 				continue
 			}
 
@@ -608,14 +701,15 @@ func (s State) randomTarget() (target, error) {
 		}
 	}
 	if len(targets) == 0 {
-		return target{}, errors.New("No targets to damage")
+		return s, target{}, errors.New("No targets to damage")
 	}
-	return targets[rand.Intn(len(targets))], nil
+	i := s.rand().IntN(len(targets))
+	return s, targets[i], nil
 }
 
 func (c Card) atk(n int) (Attack, error) {
 	if c.CName == Dragonius {
-		return Attack{}, DragoniusAtkErr{}
+		return c.Atk0, DragoniusAtkErr{}
 	}
 	switch n { 
 	case 0:
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_PlayChecksBeforeMutating(t *testing.T) {
+	g, _ := newGame(2, DefaultRules())
+	for range MaxPermLen {
+		g, _, _ = g.addPerm(0, Perm{CName: Aquarius})
+	}
+	g = g.setMana(3)
+	g.Players[0].discountSpell = true
+
+	g, err := g.play(0, CardFromName(cards, Librarius))
+	if err == nil {
+		t.Fatal("expected full board error")
+	}
+	if g.Mana != 3 || !g.Players[0].discountSpell {
+		t.Error("failed play spent mana")
+	}
+
+	g = testGame([]CardName{Meteorus})
+	g, _ = g.activatePerm(PermTarget{0, 0})
+	if g.Permanents[PermTarget{0, 0}].Activated {
+		t.Error("failed activation used up the perm")
+	}
+}
+
+func Test_EndTurnDropsAwait(t *testing.T) {
+	g := testGame([]CardName{Librarian, Angel}, []CardName{Librarian})
+	g.Testing = false
+	g.Players[0].Hand = []CardName{PyrusBalio}
+	g.Players[1].Hand = nil
+	g.Mana = 6
+
+	g, err := g.Apply(PlayFromHand{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(g.LegalActions(0), Action(EndTurn{})) {
+		t.Error("expected EndTurn not to be offered while a target is pending")
+	}
+
+	g, err = g.Apply(EndTurn{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.CurrentPlayer != 1 || g.awaiting.isTrue {
+		t.Fatal("expected the target to be dropped at the end of the turn")
+	}
+	if !slices.Equal(g.Players[0].Hand, []CardName{PyrusBalio}) || len(g.Players[1].Hand) != 0 {
+		t.Errorf("expected Pyrus Balio back in player 0's hand: %v %v",
+			g.Players[0].Hand, g.Players[1].Hand)
+	}
+	for _, a := range g.LegalActions(1) {
+		switch a.(type) {
+		case Target, Cancel:
+			t.Errorf("player 1 was offered %v", a)
+		}
+	}
+	if _, err := g.Apply(Target{0, 0}); err == nil {
+		t.Error("expected player 1 not to resolve player 0's spell")
+	}
+}
+
+func Test_NPlayers(t *testing.T) {
+	for n := 3; n <= MaxPlayers; n++ {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			if _, err := newGame(n, DefaultRules()); err != nil {
+				t.Fatal(err)
+			}
+			g := deckGame(n, DefaultRules(), 1, Librarian, Angel, Pyromancer)
+			for p := range n {
+				if g.Players[p].ID != playerID(p) || g.Players[p].Name != PlayerNames[p] {
+					t.Errorf("player %d not initialized: %+v", p, g.Players[p])
+				}
+			}
+			g = g.Start(cards)
+
+			for p := range n {
+				if len(g.Field[p]) != 3 || len(g.Players[p].Hand) == 0 {
+					t.Errorf("player %d wasn't set up", p)
+				}
+				if g.CurrentPlayer != playerID(p) {
+					t.Errorf("expected player %d's turn got %d", p, g.CurrentPlayer)
+				}
+				g, _ = g.Apply(EndTurn{})
+			}
+			if g.CurrentPlayer != 0 {
+				t.Errorf("expected turn order to wrap around, got %d", g.CurrentPlayer)
+			}
+		})
+	}
+}
+
+func Test_NPlayerEffects(t *testing.T) {
+	librarians := []CardName{Librarian, Librarian}
+	g := testGame([]CardName{Pyromancer, Librarian}, librarians, librarians, librarians)
+	g = g.playCards(2, Conjorius)
+
+	g, _ = g.attack(target{pID: 0, id: 0, atkNum: 1}, target{pID: 3, id: 0})
+	for p := 1; p < 4; p++ {
+		if hp := g.Field[p][1].HP; hp != 8-g.Rules.MegaSplashDmg {
+			t.Errorf("expected megaSplash to hit player %d, hp is %d", p, hp)
+		}
+	}
+
+	g = g.DoDmg(3, 1, 8)
+	if g.Players[2].moreMana != 1 {
+		t.Error("expected Conjorius to give mana to its owner")
+	}
+
+	g = g.playCards(3, Librarius)
+	for p := range 4 {
+		g.Players[p].deck = []CardName{Angel, Angel}
+	}
+	hand := len(g.Players[1].Hand)
+	g, _ = g.endTurn()
+	if len(g.Players[1].Hand) != hand+1 {
+		t.Error("expected another player's Librarius to draw for player 1")
+	}
+	if !strings.Contains(g.String(), "Dave") {
+		t.Error("expected String to include every player")
+	}
+}
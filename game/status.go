@@ -0,0 +1,109 @@
+package game
+
+import "fmt"
+
+type StatusKind int
+
+const (
+	Protected  StatusKind = iota // prevents all damage
+	Resistance                   // prevents Rules.ResistanceBlock damage
+	Stunned                      // can't attack
+	Poisoned                     // takes one damage per stack every turn
+	Silenced                     // can only use the first attack
+	numStatusKinds
+)
+
+var statusNames = [numStatusKinds]string{
+	"protected", "resistant", "stunned", "poisoned", "silenced",
+}
+
+func (k StatusKind) String() string {
+	if k < 0 || k >= numStatusKinds {
+		return fmt.Sprintf("StatusKind(%d)", int(k))
+	}
+	return statusNames[k]
+}
+
+// Turns counts the owner's turns the status has left. It's lowered
+// when the owner's turn starts or ends, depending on the kind.
+type Status struct {
+	Kind   StatusKind `json:"kind"`
+	Turns  int        `json:"turns"`
+	Stacks int        `json:"stacks"`
+}
+
+type stacking int
+
+const (
+	refresh  stacking = iota // reapplying resets the duration
+	addStack                 // reapplying adds a stack and resets the duration
+)
+
+type statusRule struct {
+	turns   int
+	stack   stacking
+	ticksOn Trigger
+}
+
+// Protection wears off when the owner's turn starts so it lasts through
+// the other players' turns. Stuns last through the owner's next turn.
+var statusRules = [numStatusKinds]statusRule{
+	Protected:  {1, refresh, OnTurnStart},
+	Resistance: {1, refresh, OnTurnStart},
+	Stunned:    {1, refresh, OnTurnEnd},
+	Poisoned:   {3, addStack, OnTurnStart},
+	Silenced:   {1, refresh, OnTurnEnd},
+}
+
+func (c Card) HasStatus(k StatusKind) bool {
+	return c.statuses[k].Turns > 0
+}
+
+func (c Card) Status(k StatusKind) (Status, bool) {
+	return c.statuses[k], c.HasStatus(k)
+}
+
+// The card's active statuses, for UIs
+func (c Card) Statuses() (res []Status) {
+	for _, st := range c.statuses {
+		if st.Turns > 0 {
+			res = append(res, st)
+		}
+	}
+	return
+}
+
+func (s State) addStatus(c *Card, k StatusKind) State {
+	rule := statusRules[k]
+	st := &c.statuses[k]
+	if st.Turns == 0 || rule.stack == refresh {
+		st.Stacks = 1
+	} else {
+		st.Stacks++
+	}
+	st.Kind = k
+	st.Turns = rule.turns
+	s.emit(StatusApplied{s.locOf(c), c.CName, *st})
+	return s
+}
+
+// Counts down the statuses of p's wizards that tick on t
+func (s State) tickStatuses(p playerID, t Trigger) State {
+	for i := range s.Field[p] {
+		c := &s.Field[p][i]
+		if t == OnTurnStart && c.HasStatus(Poisoned) {
+			s = s.doRawDmg(c, c.statuses[Poisoned].Stacks)
+		}
+		for k := range numStatusKinds {
+			if statusRules[k].ticksOn != t || !c.HasStatus(k) {
+				continue
+			}
+			c.statuses[k].Turns--
+			if c.statuses[k].Turns == 0 {
+				c.statuses[k] = Status{}
+				s.emit(StatusExpired{Loc{Wizard, int(p), i}, c.CName, k})
+			}
+		}
+	}
+	return s
+}
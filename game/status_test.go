@@ -0,0 +1,68 @@
+package game
+
+import "testing"
+
+func Test_StatusDurations(t *testing.T) {
+	g := testGame([]CardName{Librarian, Angel}, []CardName{Librarian})
+	g = g.addStatus(&g.Field[0][0], Resistance)
+	g = g.addStatus(&g.Field[0][1], Protected)
+	g = g.addStatus(&g.Field[1][0], Stunned)
+
+	g, _ = g.endTurn()
+	if !g.Field[0][0].HasStatus(Resistance) || !g.Field[0][1].HasStatus(Protected) {
+		t.Fatal("expected statuses to last through the other player's turn")
+	}
+	if _, err := g.attack(target{pID: 1, id: 0}, target{pID: 0, id: 0}); err == nil {
+		t.Error("expected stunned wizard to not be able to attack")
+	}
+	for _, a := range g.LegalActions(1) {
+		if _, ok := a.(DeclareAttack); ok {
+			t.Errorf("expected no legal attacks while stunned, got %v", a.Args())
+		}
+	}
+
+	g, _ = g.endTurn()
+	if len(g.Field[0][0].Statuses()) != 0 || len(g.Field[0][1].Statuses()) != 0 {
+		t.Error("expected statuses to expire at the start of the owner's turn")
+	}
+	if g.Field[1][0].HasStatus(Stunned) {
+		t.Error("expected stun to expire at the end of the owner's turn")
+	}
+
+	expired := 0
+	for _, e := range g.Output.Events(0) {
+		if _, ok := e.(StatusExpired); ok {
+			expired++
+		}
+	}
+	if expired != 3 {
+		t.Errorf("expected 3 expiry events, got %d", expired)
+	}
+}
+
+func Test_StatusStacking(t *testing.T) {
+	g := testGame([]CardName{Librarian}, []CardName{Librarian, Angel})
+
+	g = g.addStatus(&g.Field[1][0], Poisoned)
+	g = g.addStatus(&g.Field[1][0], Poisoned)
+	g = g.addStatus(&g.Field[1][1], Silenced)
+	g = g.addStatus(&g.Field[1][1], Silenced)
+
+	if st, _ := g.Field[1][0].Status(Poisoned); st.Stacks != 2 {
+		t.Errorf("expected poison to stack, got %d", st.Stacks)
+	}
+	if st, _ := g.Field[1][1].Status(Silenced); st.Stacks != 1 {
+		t.Errorf("expected silence to refresh, got %d stacks", st.Stacks)
+	}
+
+	g, _ = g.endTurn()
+	if hp := g.Field[1][0].HP; hp != 6 {
+		t.Errorf("expected poison to do 2 damage, hp is %d", hp)
+	}
+	if _, err := g.attack(target{pID: 1, id: 1, atkNum: 1}, target{pID: 0, id: 0}); err == nil {
+		t.Error("expected silenced wizard to not use its second attack")
+	}
+	if _, err := g.attack(target{pID: 1, id: 1}, target{pID: 1, id: 0}); err != nil {
+		t.Errorf("expected silenced wizard to use its first attack: %v", err)
+	}
+}
@@ -2,17 +2,19 @@ package game
 
 import (
 	"bytes"
+	"encoding/json"
 	//"slices"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
 	//"log"
 )
 
 type CardName int
 
-// Order matters
-//
-//go:generate stringer -type=CardName
+// Built in cards the engine refers to. Cards from other card sets get
+// numbers after these, see registry.go.
 const (
 	None CardName = iota
 	Librarian
@@ -43,7 +45,6 @@ const (
 	Retrievio
 	Extractio
 
-	MaxFieldLen = 3
 	MaxPermLen  = 7
 	MaxPlayers  = 5
 )
@@ -55,13 +56,53 @@ type Attack struct {
 }
 
 type Cdata struct {
+	ID    string `json:"id"`
+	Set   string `json:"set"`
 	Type  string `json:"type"`
 	Name  string `json:"name"`
 	Desc  string `json:"desc"`
-	Hp    int    `json:"hp"`
+	Hp    int    `json:"hp"`   // wizards only
+	Cost  int    `json:"cost"` // mana to play a perm or instant
 	Atk0  Attack `json:"atk1"`
 	Atk1  Attack `json:"atk2"`
 	CName CardName
+
+	Rarity   Rarity   `json:"rarity,omitempty"`
+	Flavor   string   `json:"flavor,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+
+	Effects CardEffects `json:"effects"`
+}
+
+type Rarity string
+
+const (
+	Common    Rarity = "common"
+	Uncommon  Rarity = "uncommon"
+	Rare      Rarity = "rare"
+	Legendary Rarity = "legendary"
+)
+
+var rarities = []Rarity{Common, Uncommon, Rare, Legendary}
+
+// Older card files put the cost of perms and instants in hp
+func (c *Cdata) UnmarshalJSON(data []byte) error {
+	type cdata Cdata
+	aux := struct {
+		*cdata
+		Cost *int `json:"cost"`
+	}{cdata: (*cdata)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	switch {
+	case aux.Cost != nil:
+		c.Cost = *aux.Cost
+	case c.Type != "wizard":
+		c.Cost, c.Hp = c.Hp, 0
+	}
+	return nil
 }
 
 type cardType int
@@ -88,9 +129,10 @@ type Card struct {
 	Atk1  Attack
 
 	//export
-	protected  bool
-	resistance bool
-	attached   CardName
+	statuses [numStatusKinds]Status
+	attached CardName
+	attacks  int
+	sick     bool
 }
 
 func (c Card) getType() cardType {
@@ -110,8 +152,6 @@ type Perm struct {
 	Cost       int
 	Activated  bool
 	AttachedTo target
-
-	card Card
 }
 
 func (p Perm) getType() cardType {
@@ -148,8 +188,10 @@ type playerID int
 type Player struct {
 	Name string
 	ID   playerID
-	Hand []CardName
-	deck []CardName
+	Team int
+	Hand    []CardName
+	Discard []CardName
+	deck    []CardName
 
 	manaCap        int
 	magicianHealth int
@@ -185,6 +227,15 @@ type Await struct {
 	spell     bool
 	spellName CardName
 	perm      PermTarget
+	caster    playerID // who played the spell or made the attack
+
+	// Hand is over Rules.MaxHandSize at the end of the turn
+	discarding bool
+
+	// What playing the awaited spell cost, refunded by Cancel
+	paid       int
+	discounted bool
+	fromHand   bool
 }
 
 type State struct {
@@ -196,11 +247,19 @@ type State struct {
 	Dragons       [MaxPlayers][]Card
 	Mana          int
 
-	manaMax int
+	Rules   GameRules
 	useMana bool
 	Testing bool
 
+	eliminated [MaxPlayers]bool
+	gameOver   bool
+	winner     playerID
+
+	phase    Phase
 	awaiting Await
+	cards    []Cdata
+	seed     uint64
+	rng      rand.PCG
 	Logs     *bytes.Buffer
 	//Output   *log.Logger
 
@@ -211,26 +270,27 @@ type Output struct {
 	Messages []Message
 }
 
-func (o *Output) Printf(format string, a ...any) {
-	o.Messages = append(o.Messages, Message{
-		Text: "-" + fmt.Sprintf(format, a...),
-	})
-}
-
-func (o *Output) PrivatePrint(p playerID, format string, a ...any) {
-	o.Messages = append(o.Messages, Message{
-		Text: fmt.Sprintf(format, a...),
-		Private: true,
-		Receiver: p,
-	})
-}
-
 func (o Output) Lines(p playerID) (res []string) {
 	for _, msg := range o.Messages {
 		if msg.Private && msg.Receiver != p {
 			continue
 		}
-		res = append(res, msg.Text)
+		if _, ok := msg.Event.(silentEvent); ok {
+			continue
+		}
+		// Deck listings are one event over several lines
+		res = append(res, strings.Split(msg.Text, "\n")...)
+	}
+	return
+}
+
+// Events visible to player p, in the order they happened
+func (o Output) Events(p playerID) (res []Event) {
+	for _, msg := range o.Messages {
+		if msg.Event == nil || msg.Private && msg.Receiver != p {
+			continue
+		}
+		res = append(res, msg.Event)
 	}
 	return
 }
@@ -239,13 +299,14 @@ type Message struct {
 	Text string
 	Private bool
 	Receiver playerID
+	Event Event
 }
 
-func initArea[T Playable](pType T, numPlayers int) [MaxPlayers][]T {
+func initArea[T Playable](pType T, numPlayers, fieldLen int) [MaxPlayers][]T {
 	var maxLen int
 	switch pType.getType() {
 	case Wizard:
-		maxLen = MaxFieldLen
+		maxLen = fieldLen
 	case Permanent:
 		maxLen = MaxPermLen
 	}
@@ -265,34 +326,39 @@ func initDragons() [MaxPlayers][]Card {
 	return d
 }
 
-func NewGame(players int) (State, error) {
+var PlayerNames = [MaxPlayers]string{"Alice", "Bob", "Carol", "Dave", "Eve"}
+
+func NewGame(players int, rules GameRules) (State, error) {
 	if players < 2 || players > MaxPlayers {
 		return State{}, errors.New("Invalid number of players")
 	}
+	if err := rules.Validate(); err != nil {
+		return State{}, err
+	}
 
 	s := State{
-		Players: [MaxPlayers]Player{
-			InitPlayer(0),
-			InitPlayer(1),
-		},
 		Testing: false,
 		NumPlayers:    players,
 		CurrentPlayer: 0,
-		Field:         initArea(Card{}, players),
+		Field:         initArea(Card{}, players, rules.MaxFieldLen),
 		Dragons:       initDragons(),
 		Permanents:    make(map[PermTarget]Perm),
-		manaMax:       6,
+		Rules:         rules,
 		Output: Output{},
 	}
 
-	s.SetPlayerName(0, "Alice")
-	s.SetPlayerName(1, "Bob")
+	for p := range players {
+		s.Players[p] = InitPlayer(playerID(p))
+		s.Players[p].Team = p
+		s.SetPlayerName(playerID(p), PlayerNames[p])
+		s.Players[p].magicianHealth = rules.MaxHp
+	}
 
-	return s, nil
+	return s.SetSeed(rand.Uint64()), nil
 }
 
 func NewTestGame(players int) (State, error) {
-	s, err := NewGame(players)
+	s, err := NewGame(players, DefaultRules())
 	if err != nil {
 		return State{}, err
 	}
@@ -301,18 +367,21 @@ func NewTestGame(players int) (State, error) {
 }
 
 func (s State) String() string {
+	players := ""
+	for p := range s.NumPlayers {
+		players += fmt.Sprintf("%v\n", s.Players[p])
+	}
 	return fmt.Sprintf(
 		"Await: %v\n"+
 			"Current Player: %d\n"+
 			"mana: %d\n"+
-			"%v\n%v\n"+
+			"%s"+
 			"Fields: %v\n",
 		s.awaiting,
 		s.CurrentPlayer,
 		s.Mana,
-		s.Players[0],
-		s.Players[1],
-		s.Field,
+		players,
+		s.Field[:s.NumPlayers],
 	)
 }
 
@@ -377,24 +446,37 @@ func (s State) addPerm(id playerID, p Perm) (State, PermTarget, error) {
 	return s, PermTarget{}, errors.New("Max number of perms reached")
 }
 
+// Removed perms go to their owner's discard pile
 func (s State) removePerm(pt PermTarget) (State, error) {
+	s, p, err := s.takePerm(pt)
+	if err != nil {
+		return s, err
+	}
+	return s.discard(pt.pID, p.CName), nil
+}
+
+func (s State) takePerm(pt PermTarget) (State, Perm, error) {
 	p, ok := s.Permanents[pt]
 	if !ok {
-		return s, errors.New("Couldnt find perm to remove")
+		return s, p, errors.New("Couldnt find perm to remove")
 	}
 
 	delete(s.Permanents, pt)
+	if p.CName == Dragonius {
+		s.Dragons[pt.pID][pt.id] = Card{}
+	}
+	s.emit(PermRemoved{pt.pID, p.CName})
 
+	ctx := &triggerCtx{player: pt.pID, name: p.CName, perm: pt}
 	c, err := s.cardFromTarget(p.AttachedTo)
-	if err != nil {
-		return s, nil
+	if err == nil && c.attached == p.CName {
+		c.attached = None
+		ctx.card = c
 	}
 
-	c.attached = None
-	if p.CName == Vitalius {
-		s = s.doRawDmg(c, 2)
+	// The perm isn't in play anymore so fire won't reach it
+	if f, ok := permTriggers[s.cardEffects(p.CName).Passive][OnLeavePlay]; ok {
+		s = f(s, pt, ctx)
 	}
-
-	s.Output.Printf("Removed %s", p.CName)
-	return s, nil
+	return s.fire(OnLeavePlay, ctx), p, nil
 }
@@ -0,0 +1,68 @@
+package game
+
+import "errors"
+
+var InvalidTeamsErr = errors.New("Invalid teams")
+var TargetTeammateErr = TargetErr{"Can't attack a teammate"}
+
+// Puts player p on teams[p]. Teammates are allies for card effects and win
+// together. Every player starts on their own team.
+func (s State) SetTeams(teams ...int) (State, error) {
+	if len(teams) != s.NumPlayers {
+		return s, InvalidTeamsErr
+	}
+	distinct := map[int]bool{}
+	for _, t := range teams {
+		distinct[t] = true
+	}
+	if len(distinct) < 2 {
+		return s, InvalidTeamsErr
+	}
+	for p, t := range teams {
+		s.Players[p].Team = t
+	}
+	return s, nil
+}
+
+func (s State) sameTeam(a, b playerID) bool {
+	return s.Players[a].Team == s.Players[b].Team
+}
+
+// Other players on p's team, eliminated or not
+func (s State) Teammates(p playerID) (res []playerID) {
+	for q := range s.NumPlayers {
+		if playerID(q) != p && s.sameTeam(p, playerID(q)) {
+			res = append(res, playerID(q))
+		}
+	}
+	return
+}
+
+// Teammates can only be hurt on purpose with Rules.FriendlyFire. A
+// player's own cards can always be targeted.
+func (s State) checkHostile(src playerID, t target) error {
+	if s.Rules.FriendlyFire || t.pID == src || !s.sameTeam(src, t.pID) {
+		return nil
+	}
+	return TargetTeammateErr
+}
+
+func (s State) remainingTeams() (res []int) {
+	seen := map[int]bool{}
+	for _, p := range s.remainingPlayers() {
+		if t := s.Players[p].Team; !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	return
+}
+
+// Every player on the winning team, including eliminated ones
+func (s State) Winners() []playerID {
+	w, ok := s.Winner()
+	if !ok {
+		return nil
+	}
+	return append([]playerID{w}, s.Teammates(w)...)
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func teamGame() State {
+	wizards := []CardName{Librarian, Shieldmancer}
+	g, _ := testGame(wizards, wizards, wizards, wizards).SetTeams(0, 1, 0, 1)
+	return g
+}
+
+func Test_SetTeams(t *testing.T) {
+	g := testGame(nil, nil, nil, nil)
+	if _, err := g.SetTeams(0, 0, 0, 0); err != InvalidTeamsErr {
+		t.Error("expected error when everyone is on one team")
+	}
+	if _, err := g.SetTeams(0, 1); err != InvalidTeamsErr {
+		t.Error("expected error for missing players")
+	}
+	g = teamGame()
+	if !slices.Equal(g.Teammates(1), []playerID{3}) {
+		t.Errorf("expected player 3 as teammate got %v", g.Teammates(1))
+	}
+}
+
+func Test_TeamVictory(t *testing.T) {
+	g := teamGame()
+	g = g.DoDmg(1, 0, 8).DoDmg(1, 1, 8)
+	g, _ = g.Apply(EndTurn{})
+	if g.GameOver() || !g.IsEliminated(1) {
+		t.Fatal("expected the game to go on while player 3 is alive")
+	}
+
+	g = g.DoDmg(3, 0, 8).DoDmg(3, 1, 8)
+	g, _ = g.Apply(EndTurn{})
+	if !g.GameOver() {
+		t.Fatal("expected the game to be over")
+	}
+	if w := g.Winners(); !slices.Equal(w, []playerID{0, 2}) {
+		t.Errorf("expected team 0 to win, got %v", w)
+	}
+}
+
+func Test_TeamAllies(t *testing.T) {
+	g := teamGame()
+	g, _ = g.attack(target{pID: 0, id: 1}, target{pID: 1, id: 0})
+	if !g.Field[2][0].HasStatus(Protected) || g.Field[1][0].HasStatus(Protected) {
+		t.Error("expected Shieldmancer to protect teammates only")
+	}
+
+	g = teamGame()
+	g = g.playCards(0, Pyromancer)
+	g, _ = g.attack(target{pID: 0, id: 2, atkNum: 1}, target{pID: 1, id: 0})
+	if g.Field[2][0].HP != 8 || g.Field[3][0].HP == 8 {
+		t.Error("expected megaSplash to skip teammates and hit enemies")
+	}
+}
+
+func Test_FriendlyFire(t *testing.T) {
+	g := teamGame()
+	if _, err := g.attack(target{pID: 0, id: 0}, target{pID: 2, id: 0}); err != TargetTeammateErr {
+		t.Errorf("expected attacking a teammate to fail, got %v", err)
+	}
+	for _, a := range g.LegalActions(0) {
+		if a, ok := a.(DeclareAttack); ok && a.Defender.PID == 2 {
+			t.Fatal("expected no legal attacks on teammates")
+		}
+	}
+
+	g.Rules.FriendlyFire = true
+	if _, err := g.attack(target{pID: 0, id: 0}, target{pID: 2, id: 0}); err != nil {
+		t.Errorf("expected friendly fire to allow it: %v", err)
+	}
+
+	g = teamGame()
+	g = g.playCards(0, Meteorus)
+	for range 20 {
+		next, err := g.Clone().activatePerm(PermTarget{0, 0})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.Field[2][0].HP != 8 || next.Field[2][1].HP != 8 {
+			t.Fatal("expected Meteorus to never hit a teammate")
+		}
+		g = g.SetSeed(g.rand().Uint64())
+	}
+}
@@ -0,0 +1,116 @@
+package game
+
+// Points in the game where perms and wizards can react
+type Trigger int
+
+const (
+	OnTurnStart Trigger = iota
+	OnTurnEnd
+	OnDamaged
+	OnDeath
+	OnPlay
+	OnAttack
+	OnLeavePlay
+)
+
+// What happened for a trigger. Not every field is set for every trigger.
+type triggerCtx struct {
+	player playerID // whose turn started/ended, who played or lost the card
+	name   CardName // card played
+	card   *Card    // damaged, dying or attacked card. For OnLeavePlay the card the perm was attached to
+	atkr   *Card    // attacking card
+	perm   PermTarget
+	amount int  // damage taken
+	killed bool // the attack killed card
+
+	// OnTurnStart handlers can change these before they're used
+	manaMax int
+	draws   int
+}
+
+type permTrigger func(s State, self PermTarget, ctx *triggerCtx) State
+type wizardTrigger func(s State, self *Card, ctx *triggerCtx) State
+
+var (
+	// Keyed by the card's passive, see CardEffects
+	permTriggers   map[string]map[Trigger]permTrigger
+	wizardTriggers map[CardName]map[Trigger]wizardTrigger
+)
+
+// Set in init since the handlers end up calling fire themselves
+func init() {
+	permTriggers = map[string]map[Trigger]permTrigger{
+		"raiseManaCap": {OnTurnStart: aquariusTurnStart},
+		"extraDraw":    {OnTurnStart: librariusTurnStart},
+		"manaOnDeath":  {OnDeath: conjoriusDeath},
+		"avengeKill":   {OnAttack: mortiusAttack},
+		"buffOnLeave":  {OnLeavePlay: vitaliusLeavePlay},
+	}
+	wizardTriggers = map[CardName]map[Trigger]wizardTrigger{}
+}
+
+// Runs the handlers of every perm in play and every living wizard,
+// perms first, both in board order. Eliminated players' cards stay on
+// the board but don't fire.
+func (s State) fire(t Trigger, ctx *triggerCtx) State {
+	for _, pt := range s.sortedPermTargets() {
+		p, ok := s.Permanents[pt]
+		if !ok || s.eliminated[pt.pID] {
+			continue
+		}
+		if f, ok := permTriggers[s.cardEffects(p.CName).Passive][t]; ok {
+			s = f(s, pt, ctx)
+		}
+	}
+	for p := range s.NumPlayers {
+		if s.eliminated[p] {
+			continue
+		}
+		for i := range s.Field[p] {
+			c := &s.Field[p][i]
+			if !c.Alive() && c != ctx.card {
+				continue
+			}
+			if f, ok := wizardTriggers[c.CName][t]; ok {
+				s = f(s, c, ctx)
+			}
+		}
+	}
+	return s
+}
+
+func aquariusTurnStart(s State, self PermTarget, ctx *triggerCtx) State {
+	ctx.manaMax++
+	return s
+}
+
+func librariusTurnStart(s State, self PermTarget, ctx *triggerCtx) State {
+	s.emit(PassiveUsed{self.pID, Librarius})
+	ctx.draws++
+	return s
+}
+
+func conjoriusDeath(s State, self PermTarget, ctx *triggerCtx) State {
+	s.emit(ManaGiven{self.pID, Conjorius, 1})
+	s.Players[self.pID].moreMana++
+	return s
+}
+
+func mortiusAttack(s State, self PermTarget, ctx *triggerCtx) State {
+	if !ctx.killed || ctx.card.attached != Mortius {
+		return s
+	}
+	c, err := s.cardFromTarget(s.Permanents[self].AttachedTo)
+	if err != nil || c != ctx.card {
+		return s
+	}
+	s.emit(Avenged{ctx.card.CName, s.locOf(ctx.atkr), ctx.atkr.CName})
+	return s.dealDmg(Mortius, ctx.atkr, s.Rules.MortiusDmg)
+}
+
+func vitaliusLeavePlay(s State, self PermTarget, ctx *triggerCtx) State {
+	if self != ctx.perm || ctx.card == nil {
+		return s
+	}
+	return s.doRawDmg(ctx.card, s.Rules.VitaliusBuff)
+}
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_Triggers(t *testing.T) {
+	var seen []Trigger
+	wizardTriggers[Shieldmancer] = map[Trigger]wizardTrigger{
+		OnDamaged: func(s State, self *Card, ctx *triggerCtx) State {
+			seen = append(seen, OnDamaged)
+			return s
+		},
+		OnTurnEnd: func(s State, self *Card, ctx *triggerCtx) State {
+			seen = append(seen, OnTurnEnd)
+			return s
+		},
+	}
+	defer delete(wizardTriggers, Shieldmancer)
+
+	g := testGame([]CardName{Shieldmancer}, []CardName{Librarian})
+	g = g.DoDmg(1, 0, 1)
+	g, _ = g.endTurn()
+
+	if !slices.Equal(seen, []Trigger{OnDamaged, OnTurnEnd}) {
+		t.Errorf("expected damage then turn end triggers, got %v", seen)
+	}
+}
+
+func Test_MortiusOnlyProtectsItsWizard(t *testing.T) {
+	g := testGame([]CardName{Librarian}, []CardName{Librarian, Librarian})
+	g.Field[1][0].attached = Mortius
+	g.Field[1][1].attached = Mortius
+	g, pt, _ := g.addPerm(1, Perm{CName: Mortius})
+	p := g.Permanents[pt]
+	p.AttachedTo = target{pID: 1, area: Wizard, id: 1}
+	g.Permanents[pt] = p
+
+	g.Field[1][0].HP = 1
+	g, _ = g.attack(target{pID: 0, id: 0}, target{pID: 1, id: 0})
+	if hp := g.Field[0][0].HP; hp != 8 {
+		t.Errorf("Mortius fired for the wrong wizard, attacker has %d hp", hp)
+	}
+
+	g.Field[1][1].HP = 1
+	g, _ = g.attack(target{pID: 0, id: 0}, target{pID: 1, id: 1})
+	if hp := g.Field[0][0].HP; hp != 8-g.Rules.MortiusDmg {
+		t.Errorf("expected Mortius to hit the attacker, attacker has %d hp", hp)
+	}
+}
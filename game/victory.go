@@ -0,0 +1,76 @@
+package game
+
+import "slices"
+
+// A player is defeated once every wizard on their field is dead, or they
+// have none, and they have no Magician left in hand to bring back.
+func (s State) isDefeated(p playerID) bool {
+	for _, c := range s.Field[p] {
+		if c.HP > 0 {
+			return false
+		}
+	}
+	return !slices.Contains(s.Players[p].Hand, Magician)
+}
+
+func (s State) eliminate(p playerID) State {
+	if s.eliminated[p] {
+		return s
+	}
+	s.eliminated[p] = true
+	s.emit(PlayerEliminated{p})
+	return s
+}
+
+func (s State) remainingPlayers() (res []playerID) {
+	for p := range s.NumPlayers {
+		if !s.eliminated[p] {
+			res = append(res, playerID(p))
+		}
+	}
+	return
+}
+
+func (s State) checkGameOver() State {
+	if s.gameOver {
+		return s
+	}
+
+	for p := range s.NumPlayers {
+		if s.isDefeated(playerID(p)) {
+			s = s.eliminate(playerID(p))
+		}
+	}
+
+	remaining := s.remainingPlayers()
+	switch len(s.remainingTeams()) {
+	case 0:
+		s.gameOver = true
+		s.emit(GameEnded{Draw: true})
+	case 1:
+		s.gameOver = true
+		s.winner = remaining[0]
+		s.emit(GameEnded{Winner: s.winner, Team: s.Players[s.winner].Team})
+	default:
+		return s
+	}
+	return s.cancelAwait()
+}
+
+func (s State) IsEliminated(p playerID) bool {
+	return s.eliminated[p]
+}
+
+func (s State) GameOver() bool {
+	return s.gameOver
+}
+
+// Second value is false while the game is running or if it ended in a
+// draw. In team games this is the first player left on the winning team,
+// see Winners.
+func (s State) Winner() (playerID, bool) {
+	if !s.gameOver || len(s.remainingTeams()) != 1 {
+		return 0, false
+	}
+	return s.winner, true
+}
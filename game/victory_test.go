@@ -0,0 +1,136 @@
+package game
+
+import "testing"
+
+func Test_GameOver(t *testing.T) {
+	g := testGame([]CardName{Librarian}, []CardName{Librarian, Librarian})
+
+	g = g.DoDmg(1, 0, 8)
+	g, _ = g.Execute(cards, "end")
+	if g.GameOver() {
+		t.Error("game ended with a wizard still alive")
+	}
+
+	g = g.DoDmg(1, 1, 8)
+	g, err := g.Execute(cards, "setmana", "1")
+	if err != nil {
+		t.Error(err)
+	}
+	if !g.GameOver() {
+		t.Fatal("expected game to be over")
+	}
+	if w, ok := g.Winner(); !ok || w != 0 {
+		t.Errorf("expected player 0 to win, got %d %t", w, ok)
+	}
+	if !g.IsEliminated(1) {
+		t.Error("expected player 1 to be eliminated")
+	}
+
+	if _, err := g.Execute(cards, "end"); err != GameOverErr {
+		t.Errorf("expected GameOverErr got %v", err)
+	}
+}
+
+func Test_GameOverDraw(t *testing.T) {
+	g := testGame([]CardName{Librarian}, []CardName{Librarian})
+	g = g.DoDmg(0, 0, 8).DoDmg(1, 0, 8)
+	g, _ = g.Execute(cards, "setmana", "0")
+	if !g.GameOver() {
+		t.Fatal("expected game to be over")
+	}
+	if _, ok := g.Winner(); ok {
+		t.Error("expected a draw")
+	}
+}
+
+func Test_MagicianInHandKeepsPlayerAlive(t *testing.T) {
+	g := testGame([]CardName{Magician, Librarian}, []CardName{Librarian})
+	g = g.doAtk(0)
+	g = g.DoDmg(0, 0, 8)
+	g, _ = g.Execute(cards, "setmana", "0")
+	if g.GameOver() {
+		t.Error("player with a Magician in hand was eliminated")
+	}
+}
+
+func Test_EmptyFieldDefeat(t *testing.T) {
+	g := testGame([]CardName{Librarian})
+	if !g.isDefeated(1) {
+		t.Error("player with an empty field and no Magician was not defeated")
+	}
+	g.Players[1].Hand = []CardName{Magician}
+	if g.isDefeated(1) {
+		t.Error("player with a Magician in hand was defeated")
+	}
+}
+
+func Test_DeckOut(t *testing.T) {
+	g, _ := newGame(3, DefaultRules())
+	g.Rules.DeckOutLoses = true
+	g = g.InitFullDeck()
+	for p := range 3 {
+		g = g.playCards(p, Librarian)
+	}
+	g.Players[1].deck = nil
+
+	g, _ = g.endTurn()
+	if !g.IsEliminated(1) {
+		t.Fatal("expected player 1 to deck out")
+	}
+	if g.CurrentPlayer != 2 {
+		t.Errorf("expected turn to pass to player 2, got %d", g.CurrentPlayer)
+	}
+	if g.GameOver() {
+		t.Error("game should continue with 2 players left")
+	}
+
+	g, _ = g.endTurn()
+	if g.CurrentPlayer != 0 {
+		t.Errorf("expected eliminated player to be skipped, got %d", g.CurrentPlayer)
+	}
+}
+
+func Test_NPlayerElimination(t *testing.T) {
+	librarian := []CardName{Librarian}
+	g := testGame(librarian, librarian, librarian)
+
+	g = g.DoDmg(1, 0, 8)
+	g, _ = g.Apply(EndTurn{})
+	if !g.IsEliminated(1) || g.GameOver() {
+		t.Fatal("expected player 1 to be out and the game to go on")
+	}
+	if g.CurrentPlayer != 2 {
+		t.Errorf("expected eliminated player to be skipped, got %d", g.CurrentPlayer)
+	}
+	for _, a := range g.LegalActions(2) {
+		if a, ok := a.(DeclareAttack); ok && a.Defender.PID == 1 {
+			t.Error("expected eliminated player's wizards to not be attackable")
+		}
+	}
+
+	g = g.DoDmg(0, 0, 8)
+	g, _ = g.Apply(EndTurn{})
+	if w, ok := g.Winner(); !g.GameOver() || !ok || w != 2 {
+		t.Errorf("expected player 2 to win, got %d %t", w, ok)
+	}
+}
+
+func Test_EliminatedPermsDontFire(t *testing.T) {
+	librarian := []CardName{Librarian}
+	g := testGame(librarian, librarian, librarian)
+	g = g.playCards(1, Librarius)
+	for p := range 3 {
+		g.Players[p].deck = []CardName{Angel, Angel}
+	}
+
+	g = g.DoDmg(1, 0, 8)
+	g, _ = g.Apply(EndTurn{})
+	if !g.IsEliminated(1) {
+		t.Fatal("expected player 1 to be out")
+	}
+	hand := len(g.Players[0].Hand)
+	g, _ = g.Apply(EndTurn{})
+	if len(g.Players[0].Hand) != hand {
+		t.Errorf("expected an eliminated player's Librarius to not draw, hand is %d", len(g.Players[0].Hand))
+	}
+}
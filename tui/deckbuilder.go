@@ -22,6 +22,11 @@ type DeckBuilder struct {
 	Cards []game.Cdata
 	cursor *Cursor
 	numCards int
+	// Cards shown in the grid, Cards can have gaps
+	grid []game.Cdata
+	// Sets the grid can be filtered by, filter 0 shows every set
+	sets []string
+	filter int
 
 	errorMsg string
 }
@@ -29,8 +34,26 @@ type DeckBuilder struct {
 type Entries struct { deck []game.DeckEntry }
 
 func NewDeckBuilder(cards []game.Cdata) *DeckBuilder {
+	s := &DeckBuilder{
+		editPath: DECK1_PATH,
+		Cards: cards, 
+		cursor: &Cursor{},
+	}
+	for _, c := range cards {
+		if c.CName != game.None && !slices.Contains(s.sets, c.Set) {
+			s.sets = append(s.sets, c.Set)
+		}
+	}
+	s.setGrid()
+	return s
+}
+
+func (s *DeckBuilder) setGrid() {
+	s.grid = slices.DeleteFunc(slices.Clone(s.Cards), func(c game.Cdata) bool {
+		return c.CName == game.None || (s.filter > 0 && c.Set != s.sets[s.filter - 1])
+	})
 	options := []Coord{} 
-	n := len(cards) - 3
+	n := len(s.grid)
 	for i := range n / Columns {
 		options = append(options, Coord{i, Columns})
 	}
@@ -39,14 +62,25 @@ func NewDeckBuilder(cards []game.Cdata) *DeckBuilder {
 	}
 	options = append(options, Coord{len(options), DefaultNumButtons})
 
-	return &DeckBuilder{
-		editPath: DECK1_PATH,
-		Cards: cards, 
-		numCards: n,
-		cursor: &Cursor{
-			Coords : options, 
-		},
+	s.numCards = n
+	s.cursor.Coords = options
+	s.cursor.Selected = CardPos{}
+}
+
+func (s *DeckBuilder) nextFilter() {
+	s.filter = (s.filter + 1) % (len(s.sets) + 1)
+	s.setGrid()
+}
+
+func (s DeckBuilder) filterName() string {
+	if s.filter == 0 {
+		return "All sets"
+	}
+	id := s.sets[s.filter - 1]
+	if set, ok := game.LookupSet(id); ok {
+		return set.Name
 	}
+	return id
 }
 
 func (s *DeckBuilder) Cursor() *Cursor {
@@ -72,7 +106,7 @@ func (s *DeckBuilder) Redraw() {
 	)
 
 	scrn := slices.Concat(
-		deckBuilderHeader(),
+		deckBuilderHeader(s.filterName()),
 		middle,
 	)
 	render(scrn)
@@ -112,8 +146,12 @@ func (s DeckBuilder) CardViewContent() (data *TextWrapIter) {
 		return
 	}
 
-	card := s.Cards[cell]  
-	data.AddLines(card.CName.String(), fmt.Sprintf("(%s)", card.Type), "")
+	card := s.grid[cell]
+	data.AddLines(card.CName.String(), fmt.Sprintf("(%s)", card.Type))
+	if set, ok := game.LookupSet(card.Set); ok {
+		data.AddLines(set.Name)
+	}
+	data.AddCardInfo(card)
 	if card.Desc != "" {
 		data.AddParagraph(card.Desc)
 	}
@@ -125,6 +163,11 @@ func (s *DeckBuilder) HandleEvent(ev termbox.Event) error {
 	if ev.Ch == 'b' {
 		return BACK
 	}
+	if ev.Ch == 'f' {
+		s.nextFilter()
+		s.Redraw()
+		return nil
+	}
 	switch ev.Key {
 	case termbox.KeyEnter:
 		if s.cursor.Selected.y != len(s.cursor.Coords) - 1 {
@@ -151,9 +194,10 @@ func (s *DeckBuilder) HandleEvent(ev termbox.Event) error {
 	return nil
 }
 
-func deckBuilderHeader() []string {
+func deckBuilderHeader(filter string) []string {
 	text := make([]string, 4) 
 	text[1] = fmt.Sprintf("%3s | %s", GameTitle, "Deck Builder")
+	text[2] = fmt.Sprintf("Set: %s (f to change)", filter)
 	return text
 }
 
@@ -162,28 +206,21 @@ func addRow(top, bottom []string) {
 }
 
 func DeckMapFromFile(path string) (m map[int]int, e error) {
-	deckFile, err := os.Open(path)
-	if err != nil {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
 		_, err = os.Create(path)
 		if err != nil {
 			return m, err
 		}
+		return m, io.EOF
 	}
-
-	data := make([]byte, 100)
-	_, err = deckFile.Read(data)
-	if err != nil {
-		return m, err
-	}
-	d, err := game.ParseDeck(data)
 	if err != nil {
 		return m, err
 	}
-
-	if err != nil {
-		return m, err
+	if len(data) == 0 {
+		return m, io.EOF
 	}
-	return d, nil
+	return game.ParseDeck(data)
 }
 
 func (s DeckBuilder) DeckList(path string) (res []string) {
@@ -193,7 +230,7 @@ func (s DeckBuilder) DeckList(path string) (res []string) {
 	content := NewTextWrapIter(DeckListWidth)
 	
 	content.AddLines(path)
-	invalid := game.ValidateDeck(s.Cards, dMap)
+	invalid := game.ValidateDeck(game.DefaultRules(), s.Cards, dMap)
 	if invalid != nil {
 		content.AddParagraph(fmt.Sprintf("(Invalid: %s)", invalid.Error()))
 		content.AddLines("")
@@ -241,7 +278,7 @@ func (s *DeckBuilder) RemoveCard() error {
 		return err
 	}
 	entries := game.SortedDeckList(s.Cards, dMap)	
-	cardID := s.cursor.Selected.y * Columns + s.cursor.Selected.x + 1
+	cardID := s.selectedCard()
 	index, found := game.SearchSortedEntries(entries, cardID)
 
 	if !found {
@@ -262,7 +299,7 @@ func (s *DeckBuilder) AddCard() error {
 		return err
 	}
 	entries := game.SortedDeckList(s.Cards, dMap)	
-	cardID := s.cursor.Selected.y * Columns + s.cursor.Selected.x + 1
+	cardID := s.selectedCard()
 	index, found := game.SearchSortedEntries(entries, cardID)
 	if found {
 		entries[index].Amount++	
@@ -276,6 +313,14 @@ func (s *DeckBuilder) AddCard() error {
 	return nil
 }
 
+func (s DeckBuilder) selectedCard() int {
+	cell := s.cursor.Selected.y * Columns + s.cursor.Selected.x
+	if cell >= len(s.grid) {
+		return 0
+	}
+	return int(s.grid[cell].CName)
+}
+
 func (s DeckBuilder) ClearDeck() {
 	os.WriteFile(s.editPath, []byte{}, 0644)
 }
@@ -284,9 +329,8 @@ func (s DeckBuilder) CardGrid() []string {
 	result := []string{}
 
 	newRow := []string{}
-	// i dont know why you have to subtract 3 but u do
 	for i := range s.numCards { 
-		newCard := cardNameImg(s.Cards, game.CardName(i + 1))
+		newCard := cardNameImg(s.Cards, s.grid[i].CName)
 		if s.cursor.IsSelected(i % Columns, i / Columns) {
 			newCard = yellow(newCard)
 		}
@@ -18,6 +18,8 @@ const (
 	Hand
 )
 
+const UNDO_LIMIT = 50
+
 var (
 	EXIT = ScreenErr{"Exit"}
 	BACK = ScreenErr{"Back"}
@@ -47,31 +49,30 @@ type Screen struct {
 	Cards []game.Cdata
 	Game game.State
 	Perms [][]game.PublicPermID
+	Replay game.Replay
+	History game.History
 }
 
-// TODO
-func (s Screen) InitGame() (game.State, error) {
-	g, err := game.NewTestGame(2)
+// Players past the second take turns using the two deck files
+func (s *Screen) InitGame(players int) (game.State, error) {
+	g, err := game.NewTestGame(players)
 	if err != nil {
 		return g, err
 	}
 	
-	deck1, err := DeckMapFromFile(DECK1_PATH)
-	if err != nil {
-		return g, err
-	}
-	deck2, err := DeckMapFromFile(DECK2_PATH)
-	if err != nil {
-		return g, err
-	}
-	g, err = g.SetDeckFromMap(0, s.Cards, deck1) 
-	if err != nil {
-		return g, err
-	}
-	g, err = g.SetDeckFromMap(1, s.Cards, deck2) 
-	if err != nil {
-		return g, err
+	paths := []string{DECK1_PATH, DECK2_PATH}
+	for p := range players {
+		deck, err := DeckMapFromFile(paths[p % len(paths)])
+		if err != nil {
+			return g, err
+		}
+		g, err = g.SetDeckFromMap(p, s.Cards, deck) 
+		if err != nil {
+			return g, err
+		}
 	}
+	s.Replay = game.NewReplay(g, s.Cards)
+	s.History = game.History{Limit: UNDO_LIMIT}
 	return g.Start(s.Cards), nil
 }
 
@@ -112,6 +113,20 @@ func (s *Screen) HandleEvent(ev termbox.Event) error {
 		case 'p':
 			s.Inp.Reset()
 			s.Execute("end")
+		case 'c':
+			s.Execute("cancel")
+		case 'u':
+			s.Undo()
+		case 'U':
+			s.UndoTurn()
+		case 'r':
+			s.Redo()
+		case 'K':
+			s.Execute("keep")
+		case 'M':
+			s.Execute("mulligan")
+		case 'S':
+			s.SaveReplay()
 		}
 
 		switch ev.Key {
@@ -119,6 +134,9 @@ func (s *Screen) HandleEvent(ev termbox.Event) error {
 			fmt.Println(s.cursor.Selected)
 			if s.cursor.SelectedYis(s.Game.NumPlayers) {
  				cmd := fmt.Sprintf("play %d", s.cursor.Selected.x)
+				if s.Game.Discarding() {
+					cmd = fmt.Sprintf("discard %d", s.cursor.Selected.x)
+				}
 				s.Execute(cmd)
 				break
 			}
@@ -176,16 +194,74 @@ func (s *Screen) Execute(command string) error {
 	command = strings.Trim(command , "\n")
 	args := strings.Split(command , " ")
 	newG, err := s.Game.Execute(s.Cards, args...)
-	s.Game = newG
 	if err != nil {
+		s.Game = newG
 		s.Output.Reset()
 		s.Output.text = err.Error()
 		return err
 	}
+	s.History.Record(s.Game, newG, args...)
+	s.Game = newG
+	s.Replay.Record(args...)
 	s.cursor.ResetCursor()
+	if s.Game.GameOver() {
+		return s.SaveReplay()
+	}
+	return err
+}
+
+// Written when the game ends or with 'S'
+func (s *Screen) SaveReplay() error {
+	if err := SaveReplay(REPLAY_PATH, s.Replay); err != nil {
+		return s.showErr(fmt.Errorf("Couldn't save the replay: %w", err))
+	}
+	s.Output.Reset()
+	s.Output.text = fmt.Sprintf("Replay saved to %s", REPLAY_PATH)
+	return nil
+}
+
+func (s *Screen) showErr(err error) error {
+	s.Output.Reset()
+	s.Output.text = err.Error()
 	return err
 }
 
+func (s *Screen) Undo() error {
+	g, err := s.History.Undo()
+	if err != nil {
+		return s.showErr(err)
+	}
+	s.Game = g
+	s.Replay.Truncate(s.Replay.Len() - 1)
+	s.cursor.ResetCursor()
+	return nil
+}
+
+func (s *Screen) UndoTurn() error {
+	g, n, err := s.History.UndoTurn()
+	if err != nil {
+		return s.showErr(err)
+	}
+	s.Game = g
+	s.Replay.Truncate(s.Replay.Len() - n)
+	s.cursor.ResetCursor()
+	return nil
+}
+
+func (s *Screen) Redo() error {
+	g, args, err := s.History.Redo()
+	if err != nil {
+		return s.showErr(err)
+	}
+	s.Game = g
+	s.Replay.Record(args...)
+	s.cursor.ResetCursor()
+	if s.Game.GameOver() {
+		return s.SaveReplay()
+	}
+	return nil
+}
+
 func (s *Screen) Update() {
 	s.Perms = s.Game.SortedPerms()
 	options := []Coord{}
@@ -206,7 +282,8 @@ func (s Screen) SelectedCardData() game.Cdata {
 	//TODO
 	hand := s.Game.Players[s.Game.CurrentPlayer].Hand
 	if s.cursor.Selected.y == len(s.cursor.Coords) - 1 && len(hand) > 0 {
-		return s.Cards[hand[s.cursor.Selected.x] - 1]
+		c, _ := game.LookupCard(s.Cards, hand[s.cursor.Selected.x])
+		return c
 	}
 	return s.Cards[6]
 }
@@ -224,8 +301,9 @@ func (s Screen) CardViewContent() (data *TextWrapIter) {
 
 	hand := s.Game.Players[s.Game.CurrentPlayer].Hand
 	if y == len(options) - 1 && len(hand) > 0 {
-		card := s.Cards[hand[x] - 1]  
-		data.AddLines(card.CName.String(), "(In Hand)", "")
+		card, _ := game.LookupCard(s.Cards, hand[x])
+		data.AddLines(card.CName.String(), "(In Hand)")
+		data.AddCardInfo(card)
 		data.AddParagraph(card.Desc)
 		data.AddWizardAttackDesc(card)
 		return
@@ -241,15 +319,16 @@ func (s Screen) CardViewContent() (data *TextWrapIter) {
 		}
 
 		data.AddLines(perm.CName.String(), "(Permanent)", "")
-		cardData := s.Cards[int(perm.CName) - 1]
+		cardData, _ := game.LookupCard(s.Cards, perm.CName)
 		data.AddParagraph(cardData.Desc)
 		return
 	}
 
 	c := s.Game.Field[coord.realRow][x]
 	data.AddLines(c.CName.String(), "(Wizard)", "") 
-	data.AddLines(fmt.Sprintf("%d/%d ", c.HP, 8), "") 
-	data.AddWizardAttackDesc(s.Cards[int(c.CName)-1])
+	data.AddLines(fmt.Sprintf("%d/%d ", c.HP, s.Game.Rules.MaxHp), "") 
+	cardData, _ := game.LookupCard(s.Cards, c.CName)
+	data.AddWizardAttackDesc(cardData)
 	return
 }
 
@@ -343,11 +422,20 @@ func (s Screen) nameOf(i int) string {
 
 func (s Screen) hand() []string {
 	text := fieldHeader("Your Hand", s.name())
+
+	playable := map[int]bool{}
+	for _, a := range s.Game.LegalActions(s.Game.CurrentPlayer) {
+		if p, ok := a.(game.PlayFromHand); ok {
+			playable[p.Index] = true
+		}
+	}
 				
 	for i, r := range s.Game.Players[s.Game.CurrentPlayer].Hand {
 		rightText := cardNameImg(s.Cards, r)
 		if s.cursor.IsSelected(i, s.Game.NumPlayers) {
 			yellow(rightText) 
+		} else if playable[i] {
+			colorAll(Green, rightText)
 		}
 		text = concat(text, rightText)
 	}
@@ -356,7 +444,8 @@ func (s Screen) hand() []string {
 }
 
 func (s Screen) field(p int) []string {
-	text := fieldHeader("Field", s.nameOf(p))
+	text := fieldHeader("Field", s.nameOf(p),
+		fmt.Sprintf("Disc: %d", len(s.Game.Players[p].Discard)))
 
 	i := 0
 	for _, r := range s.Game.Field[p] {
@@ -391,12 +480,23 @@ func (s Screen) field(p int) []string {
 func (s Screen) gameHeader() []string {
 	text := make([]string, 4) 
 	text[1] = fmt.Sprintf("%3s", GameTitle)
-	text[2] = fmt.Sprintf("Current Player: %d | Mana: %d | TestMode: %t", 
+	text[2] = fmt.Sprintf("Current Player: %d | Mana: %d | Phase: %s | TestMode: %t", 
 		s.Game.CurrentPlayer,
 		s.Game.Mana,
+		s.Game.Phase(),
 		s.Game.Testing,
 	)  
 	//text[3] = fmt.Sprintf("%s", s.Game.AwaitStatus())
+	if s.Game.GameOver() {
+		text[3] = "Game over: the game ended in a draw"
+		if w := s.Game.Winners(); len(w) > 0 {
+			names := []string{}
+			for _, p := range w {
+				names = append(names, s.Game.Players[p].String())
+			}
+			text[3] = fmt.Sprintf("Game over: %s won", strings.Join(names, " and "))
+		}
+	}
 	
 	return text
 }
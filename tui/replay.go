@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/alberttduong/card-game/game"
+	"github.com/nsf/termbox-go"
+	"os"
+	"strings"
+)
+
+const REPLAY_PATH = "replay.json"
+
+func SaveReplay(path string, r game.Replay) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func ReplayFromFile(path string) (r game.Replay, e error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	r.Rules = game.DefaultRules()
+	err = json.Unmarshal(data, &r)
+	return r, err
+}
+
+// Plays back a recorded game one command at a time
+type ReplayScreen struct {
+	*Screen
+	step int
+}
+
+func NewReplayScreen(cards []game.Cdata) *ReplayScreen {
+	return &ReplayScreen{Screen: NewScreen(cards)}
+}
+
+func (s *ReplayScreen) Load(path string) error {
+	r, err := ReplayFromFile(path)
+	if err != nil {
+		return err
+	}
+	s.Replay = r
+	return s.Seek(0)
+}
+
+func (s *ReplayScreen) Seek(step int) error {
+	g, err := s.Replay.StateAt(s.Cards, step)
+	if err != nil {
+		s.Output.text = err.Error()
+		return err
+	}
+	s.Game = g
+	s.step = step
+	s.Output.text = fmt.Sprintf("Step %d/%d", step, s.Replay.Len())
+	if step > 0 {
+		s.Output.text += ": " + strings.Join(s.Replay.Commands[step-1], " ")
+	}
+	return nil
+}
+
+func (s *ReplayScreen) HandleEvent(ev termbox.Event) error {
+	switch ev.Ch {
+	case 'b':
+		return BACK
+	case 'n':
+		if s.step < s.Replay.Len() {
+			s.Seek(s.step + 1)
+		}
+	case 'p':
+		if s.step > 0 {
+			s.Seek(s.step - 1)
+		}
+	}
+	s.Redraw()
+	return nil
+}
@@ -12,12 +12,14 @@ const (
 	Game Mode = iota
 	Deck
 	Start
+	Replay
 
 	DefaultMode = Start 
 )
 var (
 	StartDeck = ScreenErr{"Go to deckbuilder screen"}
 	StartGame = ScreenErr{"Go to game screen"}
+	StartReplay = ScreenErr{"Go to replay screen"}
 )
 
 type Screener interface {
@@ -33,6 +35,7 @@ type MainScreen struct {
 	Deck *DeckBuilder	
 	Game *Screen
 	Start *StartScreen
+	Replay *ReplayScreen
 }
 
 func InitScreen(cards []game.Cdata) *MainScreen {
@@ -41,6 +44,7 @@ func InitScreen(cards []game.Cdata) *MainScreen {
 		Start: NewStartScreen(),
 		Deck: NewDeckBuilder(cards),
 		Game: NewScreen(cards), 
+		Replay: NewReplayScreen(cards),
 	}
 	m.SetMode(DefaultMode)
 	return &m
@@ -49,13 +53,20 @@ func InitScreen(cards []game.Cdata) *MainScreen {
 func (m *MainScreen) SetMode(mode Mode) {
 	switch mode {
 	case Game:
-		g, err := m.Game.InitGame()
+		g, err := m.Game.InitGame(m.Start.players)
 		if err != nil {
-			m.lastError = err
+			m.lastError = fmt.Errorf("Invalid Deck(s):%w", err)
 			break
 		}
 		m.Current = m.Game
 		m.Game.Game = g 
+	case Replay:
+		err := m.Replay.Load(REPLAY_PATH)
+		if err != nil {
+			m.lastError = fmt.Errorf("Invalid Replay:%w", err)
+			break
+		}
+		m.Current = m.Replay
 	case Deck:
 		m.Current = m.Deck
 	default:
@@ -92,7 +103,7 @@ func (m *MainScreen) Redraw() {
 	}
 	render([]string{
 		"",
-		"Invalid Deck(s):" + m.lastError.Error()})
+		m.lastError.Error()})
 }
 
 func (m *MainScreen) HandleEvent(ev termbox.Event) error {
@@ -108,6 +119,9 @@ func (m *MainScreen) HandleEvent(ev termbox.Event) error {
 		} else if key == '2' {
 			m.SetMode(Deck)	
 			return nil
+		} else if key == '3' {
+			m.SetMode(Replay)
+			return nil
 		}
 	}
 
@@ -122,6 +136,8 @@ func (m *MainScreen) HandleEvent(ev termbox.Event) error {
 		m.SetMode(Deck)
 	} else if err == StartGame {
 		m.SetMode(Game)
+	} else if err == StartReplay {
+		m.SetMode(Replay)
 	}
 	return nil
 }
@@ -216,14 +232,25 @@ func (s *Screen) resetSelected() {
 
 type StartScreen struct {
 	cursor *Cursor
+	players int
 }
 
 func (s *StartScreen) HandleEvent(ev termbox.Event) error {
+	if ev.Ch == 'p' {
+		s.players = s.players%game.MaxPlayers + 1
+		if s.players < 2 {
+			s.players = 2
+		}
+		s.Redraw()
+		return nil
+	}
 	if ev.Key == termbox.KeyEnter {
 		if s.cursor.IsSelected(0, 0) {
 			return StartGame
 		} else if s.cursor.IsSelected(0, 1) {
 			return StartDeck
+		} else if s.cursor.IsSelected(0, 2) {
+			return StartReplay
 		}
 	}
 	return nil
@@ -234,14 +261,17 @@ func NewStartScreen() *StartScreen {
 		cursor: &Cursor{Coords: []Coord{
 			{0, 1},
 			{1, 1},
+			{2, 1},
 		}}, 
+		players: 2,
 	}
 }
 
 func (s *StartScreen) Redraw() {
 	clearScreen()
-	p := RenderPadding + (len(GameTitle)+len("Start Game")-6)/2
- 	start := Box("Start Game")
+	title := fmt.Sprintf("Start Game (%d players)", s.players)
+	p := RenderPadding + (len(GameTitle)+len(title)-6)/2
+ 	start := Box(title)
 	for i, s := range start {
 		start[i] = leftPad(p, s)
 	}
@@ -250,10 +280,17 @@ func (s *StartScreen) Redraw() {
 	for i, s := range deck {
 		deck[i] = leftPad(p, s)
 	}
+	p = RenderPadding + (len(GameTitle)+len("Watch Replay")-6)/2
+	replay := Box("Watch Replay")
+	for i, s := range replay {
+		replay[i] = leftPad(p, s)
+	}
 	if s.cursor.IsSelected(0, 0) {
 		start = yellow(start)
 	} else if s.cursor.IsSelected(0, 1) {
 		deck = yellow(deck)
+	} else if s.cursor.IsSelected(0, 2) {
+		replay = yellow(replay)
 	}
 
 	text := slices.Concat(
@@ -264,13 +301,20 @@ func (s *StartScreen) Redraw() {
 		},
 		start,
 		deck,
+		replay,
 		[]string{
 			"Keys:",
 			"h, j, k, l",
 			"Enter: Select/Play",
 			"n: Small attack", "m: Big attack",
 			"i: Open Chat, Ctrl-Q: Close Chat",
+			"c: Cancel spell/attack",
+			"u: Undo, U: Undo turn, r: Redo",
+			"K: Keep hand, M: Mulligan hand",
+			"S: Save replay",
 			"b: Main Menu", 
+			"Replay: n: Next step, p: Previous step",
+			"Main Menu: p: Number of players",
 		},
 	)
 	render(text)
@@ -47,6 +47,23 @@ func (t *TextWrapIter) AddParagraph(p string) {
 	t.lines = slices.Concat(t.lines, wrapText(p, t.width))
 }
 
+// Cost, rarity, keywords and flavor text, whichever the card has
+func (t *TextWrapIter) AddCardInfo(card game.Cdata) {
+	if card.Type != "wizard" {
+		t.AddLines(fmt.Sprintf("Cost %d󰖌", card.Cost))
+	}
+	if card.Rarity != "" {
+		t.AddLines(string(card.Rarity))
+	}
+	if len(card.Keywords) > 0 {
+		t.AddParagraph(strings.Join(card.Keywords, ", "))
+	}
+	if card.Flavor != "" {
+		t.AddParagraph(card.Flavor)
+	}
+	t.AddLines("")
+}
+
 func (t *TextWrapIter) AddWizardAttackDesc(card game.Cdata) {
 	addAtk := func (a game.Attack) {	
 		empty := game.Attack{}
@@ -91,14 +108,24 @@ func wrapText(s string, width int) (lines []string) {
 	return append(lines, strings.TrimSuffix(newLine, " "))
 }
 
+// n characters of name starting at from, padded with spaces since card
+// files can have short names
+func namePart(name string, from, n int) string {
+	return fmt.Sprintf("%-*s", from+n, name)[from : from+n]
+}
+
 func cardNameImg(cards []game.Cdata, c game.CardName) []string {
-	data := cards[c - 1]
+	data, _ := game.LookupCard(cards, c)
 	name := fmt.Sprint(data.CName)
+	stat := fmt.Sprintf("│%2d󰓏│", data.Hp)
+	if data.Type != "wizard" {
+		stat = fmt.Sprintf("│%2d󰖌│", data.Cost)
+	}
 	return []string {
 		"┌───┐",
-		fmt.Sprintf("│%s│", name[0:3]),
-		fmt.Sprintf("│%s│", fmt.Sprintf("%-3s", name[3:])[:3]),
-		fmt.Sprintf("│%2d󰓏│", data.Hp), 
+		fmt.Sprintf("│%s│", namePart(name, 0, 3)),
+		fmt.Sprintf("│%s│", namePart(name, 3, 3)),
+		stat, 
 		"└───┘",
 	}
 }
@@ -106,18 +133,30 @@ func cardNameImg(cards []game.Cdata, c game.CardName) []string {
 func permImg(c game.Perm) []string {
 	return []string{
 		"┌───┐",
-		fmt.Sprintf("│%s│", fmt.Sprint(c.CName)[0:3]),
+		fmt.Sprintf("│%s│", namePart(fmt.Sprint(c.CName), 0, 3)),
 		boxMiddle(3, ""),
 		boxMiddle(3, ""),
 		"└───┘",
 	}
 }
 
+// First letter of each status, shown in the card's top border
+func statusTag(c game.Card) string {
+	tag := ""
+	for _, st := range c.Statuses() {
+		tag += strings.ToUpper(st.Kind.String()[:1])
+	}
+	if len(tag) > 3 {
+		tag = tag[:3]
+	}
+	return tag + strings.Repeat("─", 3-len(tag))
+}
+
 func cardImg(c game.Card) []string {
 	dmg0 := strings.TrimPrefix(strconv.Itoa(c.Atk0.Dmg), "-")
 	return []string{
-		"┌───┐",
-		fmt.Sprintf("│%s│", fmt.Sprint(c.CName)[0:3]),
+		fmt.Sprintf("┌%s┐", statusTag(c)),
+		fmt.Sprintf("│%s│", namePart(fmt.Sprint(c.CName), 0, 3)),
 		fmt.Sprintf("│%2d│", c.HP),
 		fmt.Sprintf("│%s󰓥%d│", dmg0, c.Atk1.Dmg),
 		"└───┘",
@@ -77,3 +77,18 @@ func Test_WrapText(t *testing.T) {
 		}
 	}
 }
+
+func Test_NamePart(t *testing.T) {
+	cases := map[[2]int]string{
+		{0, 3}: "Ox ",
+		{3, 3}: "   ",
+	}
+	for c, e := range cases {
+		if act := namePart("Ox", c[0], c[1]); act != e {
+			t.Errorf("expected %q got %q", e, act)
+		}
+	}
+	if act := namePart("Librarian", 3, 3); act != "rar" {
+		t.Errorf("expected %q got %q", "rar", act)
+	}
+}